@@ -1,29 +1,144 @@
 package core
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/liampulles/cabiria/cmd/cabiria-generate/input"
+	"github.com/liampulles/cabiria/pkg/log"
+	"github.com/liampulles/cabiria/pkg/progress"
 )
 
+// logger receives log messages, which are written to stderr.
+var logger = log.New(os.Stderr, log.Info)
+
+// SetLogger changes where log messages go, e.g. to change the level.
+func SetLogger(l *log.Logger) {
+	logger = l
+}
+
+// loggerKey is the key of the logger in a context (see withLogger).
+type loggerKey struct{}
+
+// withLogger returns a copy of ctx whose log messages go to l, e.g. to tell
+//  apart the films of a batch processed at once.
+func withLogger(ctx context.Context, l *log.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// loggerFrom returns the logger given to withLogger for ctx, or else logger.
+func loggerFrom(ctx context.Context) *log.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*log.Logger); ok {
+		return l
+	}
+	return logger
+}
+
 // Run runs the main app for cabiria-generate
 func Run(args []string) {
 	config, err := input.GetGenerateConfiguration(args)
 	failIf(err)
-	videoInfo, err := ExtractVideoInformation(&config)
-	failIf(err)
-	subsInfo, err := ExtractSubtitlesInformation(&config)
-	failIf(err)
-	prettyIntertitles, err := GeneratePrettyIntertitles(videoInfo, subsInfo, &config)
+	if config.Quiet() {
+		SetLogger(log.New(os.Stderr, config.LogLevel()))
+		SetReporter(progress.Quiet{})
+	} else {
+		// Log messages are written above the progress line
+		terminal := progress.NewTerminal(os.Stderr)
+		SetLogger(log.New(terminal.Writer(), config.LogLevel()))
+		SetReporter(terminal)
+	}
+	// Stage timings are only needed for the report
+	var timed *progress.Timed
+	if config.ReportPath() != "" {
+		timed = progress.NewTimed(reporter)
+		SetReporter(timed)
+	}
+	ctx, stop := InterruptibleContext()
+	defer stop()
+	if config.Batch() {
+		failIf(RunBatch(ctx, &config, ProcessFilm))
+		return
+	}
+	workspace, err := NewWorkspace(config.WorkDirectory())
 	failIf(err)
-	err = SaveASS(prettyIntertitles, &config, &config, videoInfo)
+	videoInfo, prettyIntertitles, err := generate(ctx, &config, workspace)
+	// Cleanup regardless of the outcome
+	cleanupErr := workspace.Remove()
 	failIf(err)
+	failIf(cleanupErr)
+	if timed != nil {
+		failIf(SaveReport(NewReport(&config, videoInfo, prettyIntertitles, timed.Timings()), config.ReportPath()))
+	}
+}
+
+func generate(ctx context.Context, config *input.GenerateConfiguration, workspace Workspace) (VideoInformation, PrettyIntertitles, error) {
+	videoInfo, err := videoInformation(ctx, config, workspace)
+	if err != nil {
+		return VideoInformation{}, PrettyIntertitles{}, err
+	}
+	videoInfo, err = ApplyCorrections(ctx, videoInfo, config)
+	if err != nil {
+		return VideoInformation{}, PrettyIntertitles{}, err
+	}
+	err = SaveRanges(ctx, videoInfo, config)
+	if err != nil {
+		return VideoInformation{}, PrettyIntertitles{}, err
+	}
+	subsInfo, err := ExtractSubtitlesInformation(ctx, config)
+	if err != nil {
+		return VideoInformation{}, PrettyIntertitles{}, err
+	}
+	prettyIntertitles, err := GeneratePrettyIntertitles(ctx, videoInfo, subsInfo, config)
+	if err != nil {
+		return VideoInformation{}, PrettyIntertitles{}, err
+	}
+	// Don't leave a partial ASS behind if interrupted
+	if ctx.Err() != nil {
+		return VideoInformation{}, PrettyIntertitles{}, ctx.Err()
+	}
+	err = SaveASS(ctx, prettyIntertitles, config, config, videoInfo)
+	return videoInfo, prettyIntertitles, err
+}
+
+// videoInformation detects the intertitles in the video, unless previously
+//  saved ranges are given.
+func videoInformation(ctx context.Context, config *input.GenerateConfiguration, workspace Workspace) (VideoInformation, error) {
+	if config.RangesPath() != "" {
+		return LoadVideoInformation(ctx, config, config.RangesPath())
+	}
+	return ExtractVideoInformation(ctx, config, workspace)
+}
+
+// InterruptibleContext returns a context which is cancelled when the process
+//  is interrupted (e.g. by Ctrl-C), so that external tools can be stopped and
+//  intermediate files removed. Only the first interrupt is caught. stop
+//  releases the context.
+func InterruptibleContext() (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			logger.Warn("Interrupted, cleaning up")
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+	return ctx, cancel
 }
 
 func failIf(err error) {
+	if errors.Is(err, context.Canceled) {
+		logger.Error("Stopped, since cabiria was interrupted")
+		os.Exit(1)
+	}
 	if err != nil {
-		fmt.Printf("Encountered fatal error: %v\n", err)
+		logger.Error("Encountered fatal error", "error", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liampulles/cabiria/cmd/cabiria-generate/input"
+	"github.com/liampulles/cabiria/pkg/progress"
+)
+
+// FilmProcessor processes a single film of a batch, with config set for that
+//  film (see GenerateConfiguration.ForFilm).
+type FilmProcessor func(ctx context.Context, config *input.GenerateConfiguration) error
+
+// RunBatch processes each film of a batch with process, config.Jobs() at a
+//  time. A film which fails does not stop the others, but an error is
+//  returned if any failed. Log messages about a film are tagged with its
+//  name, and a summary is logged once all are done.
+func RunBatch(ctx context.Context, config *input.GenerateConfiguration, process FilmProcessor) error {
+	films := config.Films()
+	logger.Info("Processing films", "count", len(films), "jobs", config.Jobs())
+	// Stage progress of films processed at once would be interleaved.
+	SetReporter(progress.Quiet{})
+
+	errs := make([]error, len(films))
+	slots := make(chan struct{}, config.Jobs())
+	var completedMutex sync.Mutex
+	completed := 0
+	var wg sync.WaitGroup
+	for i, film := range films {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, film input.Film) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			filmLogger := logger.With("film", filmName(film))
+			filmConfig := config.ForFilm(film)
+			start := time.Now()
+			// Don't start films after an interrupt
+			errs[i] = ctx.Err()
+			if errs[i] == nil {
+				errs[i] = process(withLogger(ctx, filmLogger), &filmConfig)
+			}
+
+			completedMutex.Lock()
+			completed++
+			position := fmt.Sprintf("%d/%d", completed, len(films))
+			completedMutex.Unlock()
+			if errs[i] != nil {
+				filmLogger.Error("Could not process film", "progress", position, "error", errs[i])
+				return
+			}
+			filmLogger.Info("Processed film",
+				"progress", position,
+				"elapsed", time.Since(start).Round(time.Second),
+				"ass", film.ASSPath)
+		}(i, film)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, filmName(films[i]))
+		}
+	}
+	if len(failed) > 0 {
+		logger.Warn("Processed batch",
+			"films", len(films),
+			"failed", len(failed),
+			"failed_films", strings.Join(failed, ","))
+	} else {
+		logger.Info("Processed batch", "films", len(films), "failed", 0)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d films failed", len(failed), len(films))
+	}
+	return nil
+}
+
+// ProcessFilm generates the ASS of a single film of a batch, in a workspace
+//  of its own.
+func ProcessFilm(ctx context.Context, config *input.GenerateConfiguration) error {
+	workspace, err := NewWorkspace(config.WorkDirectory())
+	if err != nil {
+		return err
+	}
+	_, _, err = generate(ctx, config, workspace)
+	// Cleanup regardless of the outcome
+	cleanupErr := workspace.Remove()
+	if err != nil {
+		return err
+	}
+	return cleanupErr
+}
+
+func filmName(film input.Film) string {
+	return filepath.Base(film.VideoPath)
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+
+	"github.com/liampulles/cabiria/pkg/intertitle/correct"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+// stageCorrect is reported to the progress reporter while applying
+//  corrections
+const stageCorrect = "Applying corrections"
+
+// CorrectionsConfiguration provides configuration options necessary to
+//  correct the detected intertitle ranges by hand.
+type CorrectionsConfiguration interface {
+	// CorrectionsPath is the YAML file of corrections to apply, or empty if
+	//  there are none.
+	CorrectionsPath() string
+}
+
+// ApplyCorrections applies the configured corrections to the intertitle
+//  ranges of videoInfo, if a corrections file is configured. Corrections
+//  which could not be applied are logged and skipped, since detection may
+//  have since fixed the mistake.
+func ApplyCorrections(ctx context.Context, videoInfo VideoInformation, config CorrectionsConfiguration) (VideoInformation, error) {
+	if config.CorrectionsPath() == "" {
+		return videoInfo, nil
+	}
+	logger := loggerFrom(ctx)
+	reporter.Start(stageCorrect, 0)
+	corrections, err := correct.Load(config.CorrectionsPath())
+	if err != nil {
+		return VideoInformation{}, err
+	}
+	corrected, skipped := correct.Apply(videoInfo.IntertitleRanges, corrections, videoInfo.VideoFPS)
+	reporter.Finish(stageCorrect)
+	for _, i := range skipped {
+		logger.Warn("Skipped correction, since no intertitle is at its time or it would end before it starts",
+			"correction", i+1,
+			"action", corrections[i].Action,
+			"at", cabiriaTime.ToTimecode(cabiriaTime.FromDuration(corrections[i].At), 3))
+	}
+	logger.Info("Applied corrections",
+		"path", config.CorrectionsPath(),
+		"applied", len(corrections)-len(skipped),
+		"intertitles", len(corrected))
+
+	videoInfo.IntertitleRanges = corrected
+	return videoInfo, nil
+}
@@ -1,57 +1,79 @@
 package core
 
 import (
-	"fmt"
-	"os"
+	"context"
 
+	"github.com/liampulles/cabiria/pkg/log"
 	"github.com/liampulles/cabiria/pkg/subtitle/style"
 
 	"github.com/liampulles/cabiria/pkg/subtitle"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
 )
 
+// stagePretty is reported to the progress reporter while generating
+const stagePretty = "Generating pretty intertitles"
+
 // PrettyConfiguration provides configuration options which are needed
 //  to stylize subtitles
 type PrettyConfiguration interface {
-	FrameOutputDirectory() string
 	FontName() string
 	FontSize() uint
+	Outline() uint
+	Alignment() uint
+	MarginLeft() uint
+	MarginRight() uint
+	MarginVertical() uint
 }
 
 // PrettyIntertitles can be exported to ASS.
 type PrettyIntertitles struct {
 	GlobalStyle style.Style
 	Subtitles   []subtitle.Subtitle
+	// Assignments record which intertitle each subtitle was aligned to.
+	Assignments []subtitle.Assignment
 }
 
 // GeneratePrettyIntertitles uses extracted video and subtitle information
 //  to generate PrettyIntertitles.
 func GeneratePrettyIntertitles(
+	ctx context.Context,
 	videoInfo VideoInformation,
 	subInfo SubtitlesInformation,
 	config PrettyConfiguration) (PrettyIntertitles, error) {
-	fmt.Print("Generating pretty intertitles")
+	reporter.Start(stagePretty, 0)
 
 	// Correct sub timing slice to intertitles, and copy style
-	correctedSubs := subtitle.AlignSubtitles(subInfo.Subtitles, videoInfo.IntertitleRanges)
-	printProgressDot()
-
-	// Delete extracted frames
-	err := os.RemoveAll(config.FrameOutputDirectory())
-	if err != nil {
-		return PrettyIntertitles{}, err
-	}
-	printProgressDot()
+	correctedSubs, assignments := subtitle.AlignSubtitlesWithAssignments(subInfo.Subtitles, videoInfo.IntertitleRanges)
+	logUnmatched(loggerFrom(ctx), assignments)
 
-	printDone()
+	reporter.Finish(stagePretty)
 	return PrettyIntertitles{
 		GlobalStyle: globalStyle(config),
 		Subtitles:   correctedSubs,
+		Assignments: assignments,
 	}, nil
 }
 
+// logUnmatched warns logger about subtitles which overlap no intertitle,
+//  since they keep their original timing.
+func logUnmatched(logger *log.Logger, assignments []subtitle.Assignment) {
+	for _, elem := range assignments {
+		if !elem.Matched {
+			logger.Warn("Subtitle overlaps no intertitle, so keeps its timing",
+				"start", cabiriaTime.ToDuration(elem.Subtitle.StartTime),
+				"text", elem.Subtitle.Text)
+		}
+	}
+}
+
 func globalStyle(config PrettyConfiguration) style.Style {
 	return style.Style{
-		FontName: config.FontName(),
-		FontSize: config.FontSize(),
+		FontName:       config.FontName(),
+		FontSize:       config.FontSize(),
+		Outline:        config.Outline(),
+		Alignment:      config.Alignment(),
+		MarginLeft:     config.MarginLeft(),
+		MarginRight:    config.MarginRight(),
+		MarginVertical: config.MarginVertical(),
 	}
 }
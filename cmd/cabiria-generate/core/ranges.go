@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+
+	"github.com/liampulles/cabiria/pkg/intertitle/read"
+	"github.com/liampulles/cabiria/pkg/intertitle/write"
+)
+
+// Stages of loading and saving ranges, as reported to the progress reporter
+const (
+	stageLoadRanges = "Loading intertitle ranges"
+	stageSaveRanges = "Saving intertitle ranges"
+)
+
+// RangesConfiguration provides configuration options necessary to save the
+//  detected intertitle ranges.
+type RangesConfiguration interface {
+	// RangesOutPath is where to save the ranges, or empty if they should
+	//  not be saved.
+	RangesOutPath() string
+}
+
+// LoadVideoInformation is like ExtractVideoInformation, but loads previously
+//  saved intertitle ranges from rangesPath instead of detecting them. The
+//  video is only probed. Since the frames are not compared, the film FPS is
+//  taken to be the FPS of the video.
+func LoadVideoInformation(ctx context.Context, config VideoConfiguration, rangesPath string) (VideoInformation, error) {
+	logger := loggerFrom(ctx)
+	basicInfo, err := probeVideo(ctx, config)
+	if err != nil {
+		return VideoInformation{}, err
+	}
+
+	reporter.Start(stageLoadRanges, 0)
+	interRanges, err := read.Ranges(rangesPath)
+	if err != nil {
+		return VideoInformation{}, err
+	}
+	reporter.Finish(stageLoadRanges)
+	for i, elem := range interRanges {
+		if !elem.Timed && elem.FPS != basicInfo.FPS {
+			logger.Warn("Loaded intertitle has a different FPS to the video, so may be mistimed",
+				"intertitle", i+1,
+				"fps", elem.FPS,
+				"video_fps", basicInfo.FPS)
+		}
+	}
+	logger.Info("Loaded intertitles", "path", rangesPath, "count", len(interRanges))
+
+	return VideoInformation{
+		VideoFPS:                basicInfo.FPS,
+		VideoFilmFPS:            basicInfo.FPS,
+		VideoHeight:             basicInfo.Height,
+		VideoWidth:              basicInfo.Width,
+		VideoSampleAspectRatio:  basicInfo.SampleAspectRatio,
+		VideoDisplayAspectRatio: basicInfo.DisplayAspectRatio,
+		IntertitleRanges:        interRanges,
+	}, nil
+}
+
+// SaveRanges writes the detected intertitle ranges to disk, in the format
+//  given by the extension of the path, if a path is configured.
+func SaveRanges(ctx context.Context, videoInfo VideoInformation, config RangesConfiguration) error {
+	if config.RangesOutPath() == "" {
+		return nil
+	}
+	reporter.Start(stageSaveRanges, 0)
+	err := write.Ranges(videoInfo.IntertitleRanges, config.RangesOutPath())
+	if err != nil {
+		return err
+	}
+	reporter.Finish(stageSaveRanges)
+	loggerFrom(ctx).Info("Saved intertitle ranges", "path", config.RangesOutPath())
+	return nil
+}
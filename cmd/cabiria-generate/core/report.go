@@ -0,0 +1,147 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/meta"
+	"github.com/liampulles/cabiria/pkg/progress"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+// ReportConfiguration provides the inputs and outputs of a run to describe
+//  in its report.
+type ReportConfiguration interface {
+	VideoPath() string
+	SubtitlesPath() string
+	ASSPath() string
+	PredictorPath() string
+}
+
+// Report describes what a run detected and how long it took, so that it can
+//  be reviewed without the video. Times are in seconds.
+type Report struct {
+	Version     string             `json:"version"`
+	Video       string             `json:"video"`
+	Subtitles   string             `json:"subtitles"`
+	Output      string             `json:"output"`
+	Model       string             `json:"model"`
+	FPS         float64            `json:"fps"`
+	FilmFPS     float64            `json:"film_fps"`
+	Intertitles []ReportIntertitle `json:"intertitles"`
+	Assignments []ReportAssignment `json:"assignments"`
+	Stages      []ReportStage      `json:"stages"`
+}
+
+// ReportIntertitle is a detected intertitle. Confidence is the mean
+//  confidence of the predictor over its frames, or nil if the intertitles
+//  were loaded rather than detected.
+type ReportIntertitle struct {
+	StartFrame int      `json:"start_frame"`
+	EndFrame   int      `json:"end_frame"`
+	Start      float64  `json:"start"`
+	End        float64  `json:"end"`
+	Foreground string   `json:"foreground"`
+	Background string   `json:"background"`
+	Confidence *float64 `json:"confidence"`
+}
+
+// ReportAssignment is a subtitle (with its original timing), and the index
+//  of the intertitle it was aligned to - or nil if it overlapped none.
+type ReportAssignment struct {
+	Text       string  `json:"text"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Intertitle *int    `json:"intertitle"`
+}
+
+// ReportStage is how long a stage took.
+type ReportStage struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+// NewReport describes a finished run.
+func NewReport(config ReportConfiguration,
+	videoInfo VideoInformation,
+	prettyIntertitles PrettyIntertitles,
+	timings []progress.StageTiming) Report {
+
+	report := Report{
+		Version:     meta.ProgramVersion,
+		Video:       config.VideoPath(),
+		Subtitles:   config.SubtitlesPath(),
+		Output:      config.ASSPath(),
+		Model:       config.PredictorPath(),
+		FPS:         videoInfo.VideoFPS,
+		FilmFPS:     videoInfo.VideoFilmFPS,
+		Intertitles: make([]ReportIntertitle, len(videoInfo.IntertitleRanges)),
+		Assignments: make([]ReportAssignment, len(prettyIntertitles.Assignments)),
+		Stages:      make([]ReportStage, len(timings)),
+	}
+	for i, elem := range videoInfo.IntertitleRanges {
+		report.Intertitles[i] = ReportIntertitle{
+			StartFrame: elem.StartFrame,
+			EndFrame:   elem.EndFrame,
+			Start:      seconds(elem.Start()),
+			End:        seconds(elem.End()),
+			Foreground: cabiriaImage.HexColor(elem.Style.ForegroundColor),
+			Background: cabiriaImage.HexColor(elem.Style.BackgroundColor),
+		}
+		if videoInfo.FrameConfidences != nil {
+			confidence := intertitle.MeanConfidence(videoInfo.FrameConfidences, elem)
+			report.Intertitles[i].Confidence = &confidence
+		}
+	}
+	for i, elem := range prettyIntertitles.Assignments {
+		report.Assignments[i] = ReportAssignment{
+			Text:  elem.Subtitle.Text,
+			Start: seconds(elem.Subtitle.StartTime),
+			End:   seconds(elem.Subtitle.EndTime),
+		}
+		if elem.Matched {
+			report.Assignments[i].Intertitle = indexOfRange(videoInfo.IntertitleRanges, elem.Intertitle)
+		}
+	}
+	for i, elem := range timings {
+		report.Stages[i] = ReportStage{
+			Name:    elem.Stage,
+			Seconds: elem.Duration.Seconds(),
+		}
+	}
+	return report
+}
+
+// SaveReport writes a report to disk, as JSON.
+func SaveReport(report Report, reportPath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(reportPath, append(data, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("could not save report: %v", err)
+	}
+	logger.Info("Saved report", "path", reportPath)
+	return nil
+}
+
+// indexOfRange finds the range with the same bounds as target. Detected
+//  ranges do not overlap, so the bounds are unique.
+func indexOfRange(ranges []intertitle.Range, target intertitle.Range) *int {
+	for i, elem := range ranges {
+		if elem.Start().Equal(target.Start()) && elem.End().Equal(target.End()) {
+			index := i
+			return &index
+		}
+	}
+	return nil
+}
+
+func seconds(t time.Time) float64 {
+	return cabiriaTime.ToDuration(t).Seconds()
+}
@@ -1,17 +1,31 @@
 package core
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/liampulles/cabiria/pkg/file"
 	"github.com/liampulles/cabiria/pkg/subtitle"
 	"github.com/liampulles/cabiria/pkg/subtitle/read"
 	"github.com/liampulles/cabiria/pkg/subtitle/write"
 )
 
+// Stages of subtitle processing, as reported to the progress reporter
+const (
+	stageSubtitles = "Extracting subtitle information"
+	stageSaveASS   = "Saving ASS"
+)
+
 // SubtitlesConfiguration provides configuration options necessary
 //  to extract subtitles.
 type SubtitlesConfiguration interface {
-	SRTPath() string
+	SubtitlesPath() string
+	SubtitlesEncoding() string
+}
+
+// OutputConfiguration provides configuration options necessary to save
+//  the generated subtitles.
+type OutputConfiguration interface {
 	ASSPath() string
 }
 
@@ -23,16 +37,31 @@ type SubtitlesInformation struct {
 
 // ExtractSubtitlesInformation will read in a subtitle given by the configuration,
 //  and provide relevant information about the subtitle as output.
-func ExtractSubtitlesInformation(config SubtitlesConfiguration) (SubtitlesInformation, error) {
-	fmt.Print("Extracting subtitle information")
+func ExtractSubtitlesInformation(ctx context.Context, config SubtitlesConfiguration) (SubtitlesInformation, error) {
+	logger := loggerFrom(ctx)
+	reporter.Start(stageSubtitles, 0)
 	// Load subs
-	subs, err := read.SRT(config.SRTPath())
+	encoding := config.SubtitlesEncoding()
+	if encoding == "" {
+		detected, err := file.DetectFileEncoding(config.SubtitlesPath())
+		if err != nil {
+			return SubtitlesInformation{}, err
+		}
+		encoding = detected
+		logger.Debug("Detected subtitle encoding", "path", config.SubtitlesPath(), "encoding", encoding)
+	}
+	subs, warnings, err := read.Subtitles(config.SubtitlesPath(), encoding)
 	if err != nil {
 		return SubtitlesInformation{}, err
 	}
-	printProgressDot()
-	printProgressDot()
-	printDone()
+	reporter.Finish(stageSubtitles)
+	for _, elem := range warnings {
+		logger.Warn("Problem in subtitles",
+			"path", config.SubtitlesPath(),
+			"line", elem.Line,
+			"problem", elem.Message)
+	}
+	logger.Debug("Read subtitles", "path", config.SubtitlesPath(), "count", len(subs))
 
 	return SubtitlesInformation{
 		Subtitles: subs,
@@ -41,30 +70,36 @@ func ExtractSubtitlesInformation(config SubtitlesConfiguration) (SubtitlesInform
 
 // SaveASS takes a representation of "pretty" subtitles and writes them to disk,
 //  in ASS format.
-func SaveASS(prettyIntertitles PrettyIntertitles,
-	subConfig SubtitlesConfiguration,
+func SaveASS(ctx context.Context,
+	prettyIntertitles PrettyIntertitles,
+	outputConfig OutputConfiguration,
 	videoConfig VideoConfiguration,
 	videoInfo VideoInformation) error {
-	fmt.Print("Saving ASS")
+	reporter.Start(stageSaveASS, 0)
 
 	// Save ASS
 	config := ASSConfiguration{
-		videoPath:   videoConfig.VideoPath(),
-		videoWidth:  videoInfo.VideoWidth,
-		videoHeight: videoInfo.VideoHeight,
+		videoPath:               videoConfig.VideoPath(),
+		videoWidth:              videoInfo.VideoWidth,
+		videoHeight:             videoInfo.VideoHeight,
+		videoSampleAspectRatio:  videoInfo.VideoSampleAspectRatio,
+		videoDisplayAspectRatio: videoInfo.VideoDisplayAspectRatio,
 	}
-	printProgressDot()
-	write.ASS(prettyIntertitles.Subtitles, prettyIntertitles.GlobalStyle, &config, subConfig.ASSPath())
-	printProgressDot()
-	printDone()
+	if err := write.ASS(prettyIntertitles.Subtitles, prettyIntertitles.GlobalStyle, &config, outputConfig.ASSPath()); err != nil {
+		return fmt.Errorf("could not save ASS to %s: %v", outputConfig.ASSPath(), err)
+	}
+	reporter.Finish(stageSaveASS)
+	loggerFrom(ctx).Info("Saved ASS", "path", outputConfig.ASSPath())
 	return nil
 }
 
 // ASSConfiguration is the config necessary to generate and save an ASS file
 type ASSConfiguration struct {
-	videoPath   string
-	videoWidth  int
-	videoHeight int
+	videoPath               string
+	videoWidth              int
+	videoHeight             int
+	videoSampleAspectRatio  float64
+	videoDisplayAspectRatio float64
 }
 
 // VideoPath is the path to the input video
@@ -81,3 +116,15 @@ func (ac *ASSConfiguration) VideoWidth() int {
 func (ac *ASSConfiguration) VideoHeight() int {
 	return ac.videoHeight
 }
+
+// VideoSampleAspectRatio is the width:height ratio of a single pixel of the
+//  video, or 0.0 if unknown
+func (ac *ASSConfiguration) VideoSampleAspectRatio() float64 {
+	return ac.videoSampleAspectRatio
+}
+
+// VideoDisplayAspectRatio is the width:height ratio of the video as it
+//  should be displayed, or 0.0 if unknown
+func (ac *ASSConfiguration) VideoDisplayAspectRatio() float64 {
+	return ac.videoDisplayAspectRatio
+}
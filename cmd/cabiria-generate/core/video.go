@@ -1,175 +1,472 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"math"
+	"os"
+	"path"
 	"runtime"
+	"sort"
 	"sync"
+	"time"
 
-	"github.com/liampulles/cabiria/pkg/array"
-	"github.com/liampulles/cabiria/pkg/image"
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
 	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/log"
 	cabiriaMath "github.com/liampulles/cabiria/pkg/math"
+	"github.com/liampulles/cabiria/pkg/progress"
+	"github.com/liampulles/cabiria/pkg/sequence"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
 	"github.com/liampulles/cabiria/pkg/video"
 
 	"github.com/jinzhu/copier"
 )
 
+const (
+	frameWidth  = 64
+	frameHeight = 48
+)
+
 // VideoConfiguration provides configuration options necessary to extract video information
 type VideoConfiguration interface {
 	VideoPath() string
-	FrameOutputDirectory() string
+	ProbeBackend() string
 	PredictorPath() string
-	SmoothingClosingThreshold() uint
-	SmoothingOpeningThreshold() uint
+	Smoothing() string
+	SmoothingClosingThreshold() float64
+	SmoothingOpeningThreshold() float64
+	ConfidenceHigh() float64
+	ConfidenceLow() float64
+	ReviewBelow() float64
+	SnapTolerance() float64
 }
 
 // VideoInformation provides relevant information about the video (including
 //  the intertitles)
 type VideoInformation struct {
-	VideoFPS         float64
-	VideoWidth       int
-	VideoHeight      int
-	IntertitleRanges []intertitle.Range
+	VideoFPS                float64
+	VideoFilmFPS            float64
+	VideoWidth              int
+	VideoHeight             int
+	VideoSampleAspectRatio  float64
+	VideoDisplayAspectRatio float64
+	IntertitleRanges        []intertitle.Range
+	// FrameConfidences is how confident the predictor is that each frame is
+	//  an intertitle, or nil if the ranges were loaded rather than detected.
+	FrameConfidences []float64
 }
 
-// ExtractVideoInformation reads relevant information from the input video
-func ExtractVideoInformation(config VideoConfiguration) (VideoInformation, error) {
-	fmt.Print("Extracting video information")
-	// Extract frames to configured dir
-	framePaths, err := video.ExtractFrames(config.VideoPath(), config.FrameOutputDirectory())
+// Stages of ExtractVideoInformation, as reported to the progress reporter
+const (
+	stageProbe   = "Probing video"
+	stageExtract = "Extracting frames"
+	stagePredict = "Predicting intertitles"
+	stageStyle   = "Extracting intertitle styles"
+)
+
+// ExtractVideoInformation reads relevant information from the input video.
+//  Intermediate files are kept in workspace. External tools are killed if
+//  ctx is done.
+func ExtractVideoInformation(ctx context.Context, config VideoConfiguration, workspace Workspace) (VideoInformation, error) {
+	logger := loggerFrom(ctx)
+	// Prepare dir for the frames kept for style extraction
+	err := os.MkdirAll(workspace.FrameOutputDirectory(), 0700)
 	if err != nil {
 		return VideoInformation{}, err
 	}
-	printProgressDot()
 
-	// Predict intertitle frames
-	predictions, err := predictIntertitles(framePaths, config.PredictorPath())
+	// Get some basic video info. The frame count is only used to report
+	//  progress, so an estimate is fine.
+	basicInfo, err := probeVideo(ctx, config)
 	if err != nil {
 		return VideoInformation{}, err
 	}
-	printProgressDot()
 
-	// Smooth intertitle frames
-	smoothIntertitles(predictions, config.SmoothingClosingThreshold(), config.SmoothingOpeningThreshold())
-	printProgressDot()
+	// Stream frames and predict how likely each is to be an intertitle.
+	//  Frames which may become part of an intertitle are kept.
+	analysed, err := predictIntertitles(ctx, config.VideoPath(), basicInfo.StreamIndex, workspace.FrameOutputDirectory(), config.PredictorPath(), basicInfo.FrameCount, config.ConfidenceLow())
+	if err != nil {
+		return VideoInformation{}, err
+	}
 
-	// Get some basic video info
-	basicInfo, err := video.GetBasicInformation(config.VideoPath())
+	// Threshold and smooth intertitle frames
+	filmFPS := analysed.cadence.FilmFPS(basicInfo.FPS)
+	logger.Debug("Detected cadence",
+		"cycle", analysed.cadence.Cycle,
+		"repeats", analysed.cadence.Repeats,
+		"film_fps", filmFPS)
+	smoother, err := sequence.NewSmoother(config.Smoothing(), sequence.Options{
+		Low:     config.ConfidenceLow(),
+		High:    config.ConfidenceHigh(),
+		Closing: secondsToFrames(config.SmoothingClosingThreshold(), basicInfo.FPS, filmFPS),
+		Opening: secondsToFrames(config.SmoothingOpeningThreshold(), basicInfo.FPS, filmFPS),
+	})
 	if err != nil {
 		return VideoInformation{}, err
 	}
-	printProgressDot()
+	intertitles := smoother.Smooth(analysed.confidences)
 
-	// Extract intertitle timings
-	interRanges, err := intertitle.MapRanges(predictions, basicInfo.FPS, framePaths)
+	// Extract intertitle timings and styles. Frame timestamps are preferred,
+	//  since the FPS is only an average for variable frame rate video.
+	reporter.Start(stageStyle, countIntertitles(intertitles))
+	frames := &reportingFrames{source: analysed.kept}
+	interRanges, err := intertitle.MapRangesContext(ctx, intertitles, basicInfo.FPS, analysed.timestamps, frames)
 	if err != nil {
 		return VideoInformation{}, err
 	}
-	printDone()
+	reporter.Finish(stageStyle)
+
+	// Intertitles usually start and end at a cut or fade, which is more
+	//  precise than the predictions around it.
+	logger.Debug("Detected shot boundaries",
+		"cuts", len(analysed.shots.Cuts),
+		"fade_ins", len(analysed.shots.FadeIns),
+		"fade_outs", len(analysed.shots.FadeOuts))
+	snapTolerance := int(secondsToFrames(config.SnapTolerance(), basicInfo.FPS, filmFPS))
+	interRanges = intertitle.SnapRanges(interRanges, analysed.shots.Starts(), analysed.shots.Ends(), snapTolerance, analysed.timestamps)
+	logger.Info("Detected intertitles", "count", len(interRanges))
+	flagLowConfidence(logger, interRanges, analysed.confidences, config.ReviewBelow())
 
 	return VideoInformation{
-		VideoFPS:         basicInfo.FPS,
-		VideoHeight:      basicInfo.Height,
-		VideoWidth:       basicInfo.Width,
-		IntertitleRanges: interRanges,
+		VideoFPS:                basicInfo.FPS,
+		VideoFilmFPS:            filmFPS,
+		VideoHeight:             basicInfo.Height,
+		VideoWidth:              basicInfo.Width,
+		VideoSampleAspectRatio:  basicInfo.SampleAspectRatio,
+		VideoDisplayAspectRatio: basicInfo.DisplayAspectRatio,
+		IntertitleRanges:        interRanges,
+		FrameConfidences:        analysed.confidences,
 	}, nil
 }
 
-func predictIntertitles(framePaths []string, predictorPath string) ([]bool, error) {
-	predictor, err := intertitle.Load(predictorPath)
+// flagLowConfidence warns logger of intertitles which the predictor was not
+//  confident of on average, so that they can be reviewed (and corrected if
+//  need be).
+func flagLowConfidence(logger *log.Logger, ranges []intertitle.Range, confidences []float64, reviewBelow float64) {
+	for i, elem := range ranges {
+		confidence := intertitle.MeanConfidence(confidences, elem)
+		if confidence < reviewBelow {
+			logger.Warn("Low confidence intertitle, review it",
+				"intertitle", i+1,
+				"start", cabiriaTime.ToTimecode(elem.Start(), 3),
+				"end", cabiriaTime.ToTimecode(elem.End(), 3),
+				"confidence", fmt.Sprintf("%.2f", confidence))
+		}
+	}
+}
+
+// probeVideo reads the basic information of the input video.
+func probeVideo(ctx context.Context, config VideoConfiguration) (video.Information, error) {
+	reporter.Start(stageProbe, 0)
+	prober, err := video.NewProber(config.ProbeBackend())
+	if err != nil {
+		return video.Information{}, err
+	}
+	basicInfo, err := prober.Probe(ctx, config.VideoPath())
 	if err != nil {
-		return nil, err
+		return video.Information{}, err
+	}
+	reporter.Finish(stageProbe)
+	loggerFrom(ctx).Debug("Probed video",
+		"width", basicInfo.Width,
+		"height", basicInfo.Height,
+		"fps", basicInfo.FPS,
+		"variable_frame_rate", basicInfo.VariableFrameRate,
+		"frames", basicInfo.FrameCount,
+		"stream", basicInfo.StreamIndex)
+	return basicInfo, nil
+}
+
+type frameJob struct {
+	index int
+	frame image.Image
+}
+
+type framePrediction struct {
+	index      int
+	confidence float64
+	keptPath   string
+}
+
+// analysedFrames is what is learned from streaming the frames of a video.
+type analysedFrames struct {
+	confidences []float64
+	timestamps  []time.Duration
+	cadence     video.Cadence
+	shots       video.ShotBoundaries
+	kept        keptFrames
+}
+
+// predictIntertitles streams the frames of a video stream (see
+//  video.StreamFramesContext) to be predicted. frameCount is the expected
+//  number of frames, for reporting progress. Frames with a confidence above
+//  keepAbove are kept for style extraction.
+func predictIntertitles(ctx context.Context, videoPath string, stream int, outputDirectory string, predictorPath string, frameCount int, keepAbove float64) (analysedFrames, error) {
+	predictor, err := predictors.load(predictorPath)
+	if err != nil {
+		return analysedFrames{}, err
 	}
 
 	// Split into workers
 	_, workerCount := cabiriaMath.MinMaxInt(1, runtime.NumCPU()/2)
+	jobs := make(chan frameJob, workerCount)
+	results := make(chan framePrediction, workerCount)
+	failure := newFirstError()
 	var wg sync.WaitGroup
-	framePathsDivided := divideStringArray(framePaths, workerCount)
-	predictionsDivided := setupPredictionsArrays(len(framePaths), workerCount)
-	errors := make([]error, workerCount)
 	for i := 0; i < workerCount; i++ {
 		wg.Add(1)
 		// Setup vars
 		var predictorCopy intertitle.Predictor
 		copier.Copy(&predictorCopy, &predictor)
 
-		go predictIntertitlesWorker(&predictorCopy, framePathsDivided[i], predictionsDivided[i], errors[i], &wg)
+		go predictIntertitlesWorker(&predictorCopy, outputDirectory, keepAbove, jobs, results, failure, &wg)
 	}
-	wg.Wait()
 
-	// Check for errors
-	for _, err := range errors {
-		if err != nil {
-			return nil, err
+	// Collect predictions as they come in
+	var confidences []float64
+	frames := keptFrames{paths: make(map[int]string)}
+	collected := make(chan struct{})
+	reporter.Start(stageExtract, frameCount)
+	reporter.Start(stagePredict, frameCount)
+	go func() {
+		predicted := 0
+		for result := range results {
+			predicted++
+			reporter.Update(stagePredict, predicted)
+			for len(confidences) <= result.index {
+				confidences = append(confidences, 0.0)
+			}
+			confidences[result.index] = result.confidence
+			if result.keptPath != "" {
+				frames.paths[result.index] = result.keptPath
+			}
+		}
+		close(collected)
+	}()
+
+	// Stream frames to the workers, noting duplicates, cuts and fades along
+	//  the way. If a worker fails, the stream is stopped.
+	var cadenceDetector video.CadenceDetector
+	var shotDetector video.ShotDetector
+	var last image.Image
+	timestamps, streamErr := video.StreamFramesContext(ctx, videoPath, stream, frameWidth, frameHeight, func(index int, frame image.Image) error {
+		// Each frame is only compared once, since it is relatively slow
+		diff := 0.0
+		if last != nil {
+			var err error
+			diff, err = cabiriaImage.Diff(last, frame)
+			if err != nil {
+				return fmt.Errorf("could not compare frame %d: %v", index, err)
+			}
 		}
+		last = frame
+		cadenceDetector.AddDiff(diff)
+		shotDetector.AddDiff(frame, diff)
+		select {
+		case jobs <- frameJob{index: index, frame: frame}:
+			return nil
+		case <-failure.done:
+			return failure.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, func(progress video.Progress) {
+		reporter.Update(stageExtract, progress.Frame)
+	})
+	close(jobs)
+	// Every frame was still streamed, so it can be timed by the FPS instead
+	if errors.Is(streamErr, video.ErrIncompleteTimestamps) {
+		loggerFrom(ctx).Warn("Could not read frame timestamps, so timing intertitles by the average FPS", "reason", streamErr)
+		streamErr = nil
+	}
+	// The workers may still be predicting the last frames
+	if streamErr == nil {
+		reporter.Finish(stageExtract)
 	}
+	wg.Wait()
+	close(results)
+	<-collected
 
-	// Construct predictions array
-	var predictions []bool
-	for _, elem := range predictionsDivided {
-		predictions = append(predictions, elem...)
+	// Check for errors
+	if failure.err != nil {
+		return analysedFrames{}, failure.err
+	}
+	if streamErr != nil {
+		return analysedFrames{}, streamErr
 	}
-	return predictions, nil
+	reporter.Finish(stagePredict)
+
+	frames.index()
+	return analysedFrames{
+		confidences: confidences,
+		timestamps:  timestamps,
+		cadence:     cadenceDetector.Cadence(),
+		shots:       shotDetector.Boundaries(),
+		kept:        frames,
+	}, nil
 }
 
-func predictIntertitlesWorker(predictor *intertitle.Predictor, framePaths []string, predictions []bool, err error, wg *sync.WaitGroup) {
+func predictIntertitlesWorker(predictor *intertitle.Predictor, outputDirectory string, keepAbove float64, jobs <-chan frameJob, results chan<- framePrediction, failure *firstError, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	for i, path := range framePaths {
-		// if i%3 == 0 {
-		// 	fmt.Printf("Processing: %f%%\n", float64(i*100)/float64(len(framePaths)))
-		// }
-		img, potentialErr := image.GetPNG(path)
-		if potentialErr != nil {
-			err = potentialErr
+	for job := range jobs {
+		// Stop as soon as any worker has failed
+		select {
+		case <-failure.done:
 			return
+		default:
 		}
 
-		prediction, potentialErr := predictor.PredictSingle(img)
-		if potentialErr != nil {
-			err = potentialErr
+		result, err := predictFrame(predictor, outputDirectory, keepAbove, job)
+		if err != nil {
+			failure.set(fmt.Errorf("could not process frame %d: %v", job.index, err))
 			return
 		}
-		predictions[i] = prediction
+		results <- result
 	}
-	printProgressDot()
 }
 
-func smoothIntertitles(intertitles []bool, closingThreshold, openingThreshold uint) {
-	array.CloseBoolArray(intertitles, closingThreshold)
-	array.OpenBoolArray(intertitles, openingThreshold)
-}
+func predictFrame(predictor *intertitle.Predictor, outputDirectory string, keepAbove float64, job frameJob) (framePrediction, error) {
+	confidence, err := predictor.PredictProbaSingle(job.frame)
+	if err != nil {
+		return framePrediction{}, err
+	}
 
-func divideStringArray(many []string, parts int) [][]string {
-	var divided [][]string
-	chunkSize := (len(many) + parts - 1) / parts
-	for i := 0; i < len(many); i += chunkSize {
-		end := i + chunkSize
-		if end > len(many) {
-			end = len(many)
+	// Keep possible intertitle frames, since they are needed for style
+	//  extraction.
+	keptPath := ""
+	if confidence > keepAbove {
+		keptPath = path.Join(outputDirectory, fmt.Sprintf("%s%06d.png", keptFramePrefix, job.index))
+		err = cabiriaImage.SavePNG(keptPath, job.frame)
+		if err != nil {
+			return framePrediction{}, err
 		}
-		divided = append(divided, many[i:end])
 	}
-	return divided
+
+	return framePrediction{
+		index:      job.index,
+		confidence: confidence,
+		keptPath:   keptPath,
+	}, nil
 }
 
-func setupPredictionsArrays(total int, parts int) [][]bool {
-	var divided [][]bool
-	chunkSize := (total + parts - 1) / parts
-	for i := 0; i < total; i += chunkSize {
-		end := i + chunkSize
-		if end > total {
-			end = total
-		}
-		divided = append(divided, make([]bool, end-i))
+// firstError records the first error reported by any of a group of workers,
+//  and closes done to signal the rest to stop.
+type firstError struct {
+	once sync.Once
+	err  error
+	done chan struct{}
+}
+
+func newFirstError() *firstError {
+	return &firstError{done: make(chan struct{})}
+}
+
+func (fe *firstError) set(err error) {
+	fe.once.Do(func() {
+		fe.err = err
+		close(fe.done)
+	})
+}
+
+// secondsToFrames converts a duration to a whole number of film frames, and
+//  then to the corresponding number of frames in the video stream.
+func secondsToFrames(seconds, streamFPS, filmFPS float64) uint {
+	if seconds <= 0.0 || streamFPS <= 0.0 || filmFPS <= 0.0 {
+		return 0
+	}
+	filmFrames := math.Round(seconds * filmFPS)
+	return uint(math.Round(filmFrames * streamFPS / filmFPS))
+}
+
+// predictorCache keeps loaded predictors, so that each is only loaded once
+//  when processing several films.
+type predictorCache struct {
+	mutex  sync.Mutex
+	loaded map[string]intertitle.Predictor
+}
+
+var predictors = predictorCache{loaded: make(map[string]intertitle.Predictor)}
+
+func (pc *predictorCache) load(predictorPath string) (intertitle.Predictor, error) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	if predictor, ok := pc.loaded[predictorPath]; ok {
+		return predictor, nil
+	}
+	predictor, err := intertitle.Load(predictorPath)
+	if err != nil {
+		return intertitle.Predictor{}, err
+	}
+	logger.Debug("Loaded predictor", "path", predictorPath)
+	pc.loaded[predictorPath] = predictor
+	return predictor, nil
+}
+
+const keptFramePrefix = "kept_frame"
+
+// keptFrames is a FrameSource for the frames which were predicted to be
+//  intertitles. Smoothing may add frames to an intertitle which were not kept,
+//  in which case the closest kept frame is used instead.
+type keptFrames struct {
+	paths   map[int]string
+	indices []int
+}
+
+func (kf *keptFrames) index() {
+	kf.indices = make([]int, 0, len(kf.paths))
+	for i := range kf.paths {
+		kf.indices = append(kf.indices, i)
 	}
-	return divided
+	sort.Ints(kf.indices)
 }
 
-func printProgressDot() {
-	fmt.Print(".")
+// Frame loads the kept frame closest to index.
+func (kf keptFrames) Frame(index int) (image.Image, error) {
+	if len(kf.indices) == 0 {
+		return nil, fmt.Errorf("no frames were kept, so cannot load frame %d", index)
+	}
+	closest := sort.SearchInts(kf.indices, index)
+	if closest == len(kf.indices) ||
+		(closest > 0 && index-kf.indices[closest-1] < kf.indices[closest]-index) {
+		closest--
+	}
+	return cabiriaImage.GetPNG(kf.paths[kf.indices[closest]])
 }
 
-func printDone() {
-	fmt.Print("DONE\n")
+// reportingFrames reports the progress of style extraction, which loads a
+//  frame for each intertitle.
+type reportingFrames struct {
+	source intertitle.FrameSource
+	loaded int
+}
+
+// Frame loads the frame at index from the source.
+func (rf *reportingFrames) Frame(index int) (image.Image, error) {
+	rf.loaded++
+	reporter.Update(stageStyle, rf.loaded)
+	return rf.source.Frame(index)
+}
+
+// countIntertitles counts the runs of intertitle frames.
+func countIntertitles(intertitles []bool) int {
+	count := 0
+	for i, elem := range intertitles {
+		if elem && (i == 0 || !intertitles[i-1]) {
+			count++
+		}
+	}
+	return count
+}
+
+// reporter is told of the progress of each stage.
+var reporter progress.Reporter = progress.NewTerminal(os.Stderr)
+
+// SetReporter changes how the progress of each stage is reported, e.g. to
+//  progress.Quiet{} for scripts.
+func SetReporter(r progress.Reporter) {
+	reporter = r
 }
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+// Workspace is a temporary directory which holds the intermediate files of a
+//  single run, so that concurrent runs do not interfere with each other.
+type Workspace struct {
+	dir string
+}
+
+// NewWorkspace creates a new, uniquely named Workspace within parent.
+func NewWorkspace(parent string) (Workspace, error) {
+	err := os.MkdirAll(parent, 0700)
+	if err != nil {
+		return Workspace{}, err
+	}
+	dir, err := ioutil.TempDir(parent, "cabiria-")
+	if err != nil {
+		return Workspace{}, err
+	}
+	return Workspace{dir: dir}, nil
+}
+
+// FrameOutputDirectory is the directory where frames will be extracted to.
+func (w Workspace) FrameOutputDirectory() string {
+	return path.Join(w.dir, "frames")
+}
+
+// Remove deletes the Workspace and everything in it.
+func (w Workspace) Remove() error {
+	return os.RemoveAll(w.dir)
+}
@@ -0,0 +1,150 @@
+package input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/liampulles/cabiria/cmd/internal/options"
+	"github.com/liampulles/cabiria/pkg/subtitle/read"
+)
+
+// videoExtensions are the file extensions considered to be videos when
+//  searching a batch directory.
+var videoExtensions = []string{
+	".mkv", ".mp4", ".m4v", ".avi", ".mov", ".webm", ".mpg", ".mpeg", ".ts", ".ogv", ".wmv",
+}
+
+// Film is a video and subtitle pair to generate intertitles for, along with
+//  where to save the ASS.
+type Film struct {
+	VideoPath     string
+	SubtitlesPath string
+	ASSPath       string
+}
+
+// manifestEntry is the YAML format of a Film in a manifest. srt is the old
+//  name for subs, and is still accepted.
+type manifestEntry struct {
+	VideoPath     string `yaml:"video"`
+	SubtitlesPath string `yaml:"subs"`
+	SRTPath       string `yaml:"srt"`
+	ASSPath       string `yaml:"ass"`
+}
+
+// FindFilms pairs each subtitle file (in any format read.Subtitles
+//  understands) in dir with the video which has the same basename, e.g.
+//  "Nosferatu.srt" with "Nosferatu.mkv". Subtitles without a video, or
+//  several subtitles for the same video, are an error.
+func FindFilms(dir string) ([]Film, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read batch directory: %v", err)
+	}
+	videos := make(map[string]string)
+	subtitles := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		base := name[:len(name)-len(ext)]
+		if _, ok := read.Formats[ext]; ok {
+			// Don't pick up our own output from a previous run
+			if strings.HasSuffix(base, ".cabiria") {
+				continue
+			}
+			if existing, ok := subtitles[base]; ok {
+				return nil, fmt.Errorf("%s and %s are both subtitles for %s", existing, name, base)
+			}
+			subtitles[base] = name
+		} else if isVideoExtension(ext) {
+			if existing, ok := videos[base]; ok {
+				return nil, fmt.Errorf("%s and %s are both videos for %s", existing, name, base)
+			}
+			videos[base] = name
+		}
+	}
+	bases := make([]string, 0, len(subtitles))
+	for base := range subtitles {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+
+	var films []Film
+	for _, base := range bases {
+		video, ok := videos[base]
+		if !ok {
+			return nil, fmt.Errorf("no video found for %s", subtitles[base])
+		}
+		films = append(films, newFilm(filepath.Join(dir, video), filepath.Join(dir, subtitles[base]), ""))
+	}
+	if len(films) == 0 {
+		return nil, fmt.Errorf("no video and subtitle pairs found in %s", dir)
+	}
+	return films, nil
+}
+
+// ReadManifest reads films from a YAML manifest, e.g.:
+//
+//    - video: Nosferatu.mkv
+//      subs: Nosferatu.en.srt
+//      ass: Nosferatu.en.ass # Optional
+//
+//  Relative paths are resolved against the directory of the manifest.
+func ReadManifest(manifestPath string) ([]Film, error) {
+	data, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %v", err)
+	}
+	var entries []manifestEntry
+	err = yaml.UnmarshalStrict(data, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse manifest %s: %v", manifestPath, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("manifest %s lists no films", manifestPath)
+	}
+
+	dir := filepath.Dir(manifestPath)
+	films := make([]Film, len(entries))
+	for i, entry := range entries {
+		subs, err := options.MergeAlias("subs", entry.SubtitlesPath, "srt", entry.SRTPath)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d of manifest %s: %v", i+1, manifestPath, err)
+		}
+		if entry.VideoPath == "" || subs == "" {
+			return nil, fmt.Errorf("entry %d of manifest %s must have a video and subs", i+1, manifestPath)
+		}
+		films[i] = newFilm(
+			resolvePath(dir, entry.VideoPath),
+			resolvePath(dir, subs),
+			resolvePath(dir, entry.ASSPath))
+	}
+	return films, nil
+}
+
+func newFilm(videoPath, subtitlesPath, assPath string) Film {
+	if assPath == "" {
+		assPath = *defaultASS(&subtitlesPath)
+	}
+	return Film{
+		VideoPath:     videoPath,
+		SubtitlesPath: subtitlesPath,
+		ASSPath:       assPath,
+	}
+}
+
+func isVideoExtension(ext string) bool {
+	for _, elem := range videoExtensions {
+		if ext == elem {
+			return true
+		}
+	}
+	return false
+}
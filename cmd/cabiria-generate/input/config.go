@@ -3,62 +3,295 @@ package input
 import (
 	"flag"
 	"fmt"
-	"os"
 	"path"
 
-	"github.com/liampulles/cabiria/pkg/meta"
+	"github.com/liampulles/cabiria/cmd/internal/options"
+	"github.com/liampulles/cabiria/pkg/intertitle/read"
+	"github.com/liampulles/cabiria/pkg/log"
 
-	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
 )
 
 // GenerateConfiguration provides configuration options necessary
 //  for generating pretty subtitles from an input video and subtitle
 type GenerateConfiguration struct {
-	videoPath string
-	srtPath   string
-	assPath   string
+	videoPath        string
+	subtitlesPath    string
+	encoding         string
+	assPath          string
+	workDirectory    string
+	probeBackend     string
+	predictorPath    string
+	smoothing        string
+	closingThreshold float64
+	openingThreshold float64
+	confidenceHigh   float64
+	confidenceLow    float64
+	reviewBelow      float64
+	snapTolerance    float64
+	style            style.Style
+	films            []Film
+	batch            bool
+	jobs             uint
+	quiet            bool
+	logLevel         log.Level
+	reportPath       string
+	rangesOutPath    string
+	rangesPath       string
+	correctionsPath  string
 }
 
-// GetGenerateConfiguration parses the command line to provide config
-//  for the core application
+// GetGenerateConfiguration parses the command line (and the config file, if
+//  given) to provide config for the core application. Flags take precedence
+//  over the config file.
 func GetGenerateConfiguration(args []string) (GenerateConfiguration, error) {
-	video := flag.String("video", "", "Silent film to analyze for intertitles.")
-	srt := flag.String("srt", "", "SRT subtitles to source for text.")
-	ass := flag.String("ass", "", "(Optional) ASS file to save to. Default is the SRT path with ASS extension.")
+	defaults := defaultProject()
+	config := flag.String("config", "", "(Optional) YAML project file to load options from. Flags take precedence over it.")
+	common := options.AddCommonFlags(flag.CommandLine, "Subtitles to source for text")
+	ass := flag.String("ass", "", "(Optional) ASS file to save to. Default is the subtitles path with .cabiria.ass extension.")
+	batch := flag.String("batch", "", "(Batch mode) Directory of videos and subtitles to process, paired by basename. Replaces -video and -subs.")
+	manifest := flag.String("manifest", "", "(Batch mode) YAML manifest listing the videos and subtitles to process. Replaces -video and -subs.")
+	jobs := flag.Uint("jobs", 1, "(Batch mode, optional) Number of films to process at once.")
+	font := flag.String("font", defaults.Font.Name, "(Optional) Name of the font to use in the ASS.")
+	fontSize := flag.Uint("fontsize", defaults.Font.Size, "(Optional) Size of the font to use in the ASS.")
+	report := flag.String("report", "", "(Optional) JSON file to save a report of the detected intertitles, subtitle assignments and stage timings to.")
+	ranges := flag.String("ranges", "", "(Optional) JSON file of intertitle ranges saved by -ranges-out, to use instead of detecting intertitles. Skips the slow frame analysis.")
 
-	// Custom usage message
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "%s %s (%s)\n\nUsage:\n", meta.ProgramName, meta.ProgramVersion, meta.ProgramURL)
-		flag.PrintDefaults()
+	flag.CommandLine.Parse(args[1:])
+
+	// Load the config file, and let the flags which were given override it
+	merged := defaults
+	if *config != "" {
+		var err error
+		merged, err = loadProject(*config)
+		if err != nil {
+			return GenerateConfiguration{}, err
+		}
 	}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "workdir":
+			merged.WorkDirectory = common.WorkDirectory
+		case "smoothing":
+			merged.Smoothing.Method = common.Smoothing
+		case "closing":
+			merged.Smoothing.Closing = common.Closing
+		case "opening":
+			merged.Smoothing.Opening = common.Opening
+		case "snap":
+			merged.Smoothing.Snap = common.Snap
+		case "confidence-high":
+			merged.Confidence.High = common.ConfidenceHigh
+		case "confidence-low":
+			merged.Confidence.Low = common.ConfidenceLow
+		case "review-below":
+			merged.Confidence.Review = common.ReviewBelow
+		case "font":
+			merged.Font.Name = *font
+		case "fontsize":
+			merged.Font.Size = *fontSize
+		}
+	})
 
-	flag.CommandLine.Parse(args[1:])
+	subsPath, err := options.MergeAlias("-subs", common.Subs, "-srt", common.SRT)
+	if err != nil {
+		return GenerateConfiguration{}, err
+	}
+	films, batchMode, err := getFilms(common.Video, subsPath, *ass, *batch, *manifest)
+	if err != nil {
+		return GenerateConfiguration{}, err
+	}
+	for _, film := range films {
+		if err := options.ValidateSubtitles(film.SubtitlesPath); err != nil {
+			return GenerateConfiguration{}, err
+		}
+	}
+	subsEncoding, err := options.CanonicalEncoding(common.Encoding)
+	if err != nil {
+		return GenerateConfiguration{}, err
+	}
+	if *jobs == 0 {
+		return GenerateConfiguration{}, fmt.Errorf("the -jobs parameter must be positive")
+	}
+	if batchMode && *report != "" {
+		return GenerateConfiguration{}, fmt.Errorf("the -report parameter cannot be used in batch mode")
+	}
+	if batchMode && common.RangesOut != "" {
+		return GenerateConfiguration{}, fmt.Errorf("the -ranges-out parameter cannot be used in batch mode")
+	}
+	if batchMode && common.Corrections != "" {
+		return GenerateConfiguration{}, fmt.Errorf("the -corrections parameter cannot be used in batch mode")
+	}
+	if batchMode && *ranges != "" {
+		return GenerateConfiguration{}, fmt.Errorf("the -ranges parameter cannot be used in batch mode")
+	}
+	if err := validateRanges(*ranges); err != nil {
+		return GenerateConfiguration{}, err
+	}
+	if err := options.ValidateCorrections(common.Corrections); err != nil {
+		return GenerateConfiguration{}, err
+	}
+	if err := options.ValidateRangesOut(common.RangesOut); err != nil {
+		return GenerateConfiguration{}, err
+	}
+	level, err := log.ParseLevel(common.LogLevel)
+	if err != nil {
+		return GenerateConfiguration{}, err
+	}
 
-	if *video == "" {
-		return GenerateConfiguration{}, fmt.Errorf("you must provide a -video parameter")
+	if merged.WorkDirectory == "" {
+		return GenerateConfiguration{}, fmt.Errorf("the -workdir parameter may not be empty")
+	}
+	if err := options.ValidateProbeBackend(common.Probe); err != nil {
+		return GenerateConfiguration{}, err
+	}
+	if merged.Predictor == "" {
+		return GenerateConfiguration{}, fmt.Errorf("the predictor path may not be empty")
+	}
+	err = options.ValidateDetection(merged.Smoothing.Method, merged.Smoothing.Closing, merged.Smoothing.Opening, merged.Smoothing.Snap,
+		merged.Confidence.Low, merged.Confidence.High, merged.Confidence.Review)
+	if err != nil {
+		return GenerateConfiguration{}, err
 	}
-	if *srt == "" {
-		return GenerateConfiguration{}, fmt.Errorf("you must provide a -srt parameter")
+	if merged.Font.Name == "" {
+		return GenerateConfiguration{}, fmt.Errorf("the -font parameter may not be empty")
 	}
-	if *ass == "" {
-		ass = defaultASS(srt)
+	if merged.Font.Size == 0 {
+		return GenerateConfiguration{}, fmt.Errorf("the -fontsize parameter must be positive")
+	}
+	if merged.Style.Alignment < 1 || merged.Style.Alignment > 9 {
+		return GenerateConfiguration{}, fmt.Errorf("the style alignment must be between 1 and 9, but is %d", merged.Style.Alignment)
 	}
 
 	return GenerateConfiguration{
-		videoPath: *video,
-		srtPath:   *srt,
-		assPath:   *ass,
+		videoPath:        films[0].VideoPath,
+		subtitlesPath:    films[0].SubtitlesPath,
+		encoding:         subsEncoding,
+		assPath:          films[0].ASSPath,
+		workDirectory:    merged.WorkDirectory,
+		probeBackend:     common.Probe,
+		predictorPath:    merged.Predictor,
+		smoothing:        merged.Smoothing.Method,
+		closingThreshold: merged.Smoothing.Closing,
+		openingThreshold: merged.Smoothing.Opening,
+		confidenceHigh:   merged.Confidence.High,
+		confidenceLow:    merged.Confidence.Low,
+		reviewBelow:      merged.Confidence.Review,
+		snapTolerance:    merged.Smoothing.Snap,
+		style: style.Style{
+			FontName:       merged.Font.Name,
+			FontSize:       merged.Font.Size,
+			Outline:        merged.Style.Outline,
+			Alignment:      merged.Style.Alignment,
+			MarginLeft:     merged.Style.Margins.Left,
+			MarginRight:    merged.Style.Margins.Right,
+			MarginVertical: merged.Style.Margins.Vertical,
+		},
+		films:           films,
+		batch:           batchMode,
+		jobs:            *jobs,
+		quiet:           common.Quiet,
+		logLevel:        level,
+		reportPath:      *report,
+		rangesOutPath:   common.RangesOut,
+		rangesPath:      *ranges,
+		correctionsPath: common.Corrections,
 	}, nil
 }
 
+// getFilms returns the single film given by -video and -subs, or the films
+//  of a batch.
+func getFilms(video, subs, ass, batch, manifest string) ([]Film, bool, error) {
+	if batch != "" || manifest != "" {
+		if video != "" || subs != "" || ass != "" {
+			return nil, false, fmt.Errorf("the -video, -subs and -ass parameters cannot be used in batch mode")
+		}
+		if batch != "" && manifest != "" {
+			return nil, false, fmt.Errorf("only one of -batch and -manifest may be given")
+		}
+		if batch != "" {
+			films, err := FindFilms(batch)
+			return films, true, err
+		}
+		films, err := ReadManifest(manifest)
+		return films, true, err
+	}
+
+	if video == "" {
+		return nil, false, fmt.Errorf("you must provide a -video parameter")
+	}
+	if subs == "" {
+		return nil, false, fmt.Errorf("you must provide a -subs parameter")
+	}
+	return []Film{newFilm(video, subs, ass)}, false, nil
+}
+
+// Batch is true if several films are to be processed (see Films)
+func (gc *GenerateConfiguration) Batch() bool {
+	return gc.batch
+}
+
+// Films are the films to be processed
+func (gc *GenerateConfiguration) Films() []Film {
+	return gc.films
+}
+
+// Jobs is the number of films to process at once in batch mode
+func (gc *GenerateConfiguration) Jobs() uint {
+	return gc.jobs
+}
+
+// Quiet is true if progress should not be reported
+func (gc *GenerateConfiguration) Quiet() bool {
+	return gc.quiet
+}
+
+// LogLevel is the least severe level of log messages to write
+func (gc *GenerateConfiguration) LogLevel() log.Level {
+	return gc.logLevel
+}
+
+// ReportPath is where to save a report of the run, or empty if none is
+//  wanted
+func (gc *GenerateConfiguration) ReportPath() string {
+	return gc.reportPath
+}
+
+// RangesOutPath is where to save the detected intertitle ranges, or empty if
+//  they should not be saved
+func (gc *GenerateConfiguration) RangesOutPath() string {
+	return gc.rangesOutPath
+}
+
+// RangesPath is where to load previously saved intertitle ranges from, or
+//  empty if intertitles should be detected
+func (gc *GenerateConfiguration) RangesPath() string {
+	return gc.rangesPath
+}
+
+// ForFilm returns a copy of the configuration for processing film.
+func (gc *GenerateConfiguration) ForFilm(film Film) GenerateConfiguration {
+	result := *gc
+	result.videoPath = film.VideoPath
+	result.subtitlesPath = film.SubtitlesPath
+	result.assPath = film.ASSPath
+	return result
+}
+
 // VideoPath is the path to the input video
 func (gc *GenerateConfiguration) VideoPath() string {
 	return gc.videoPath
 }
 
-// SRTPath is the path of the input subtitle
-func (gc *GenerateConfiguration) SRTPath() string {
-	return gc.srtPath
+// SubtitlesPath is the path of the input subtitles
+func (gc *GenerateConfiguration) SubtitlesPath() string {
+	return gc.subtitlesPath
+}
+
+// SubtitlesEncoding is the character encoding of the input subtitles, or
+//  empty if it should be detected
+func (gc *GenerateConfiguration) SubtitlesEncoding() string {
+	return gc.encoding
 }
 
 // ASSPath is the path of the output subtitle
@@ -66,44 +299,120 @@ func (gc *GenerateConfiguration) ASSPath() string {
 	return gc.assPath
 }
 
-// FrameOutputDirectory is the temporary directory where frames will be extracted to.
-func (gc *GenerateConfiguration) FrameOutputDirectory() string {
-	return "/tmp/cabiria/extractedFrames"
+// WorkDirectory is the directory in which a temporary directory is created
+//  for each run, to hold intermediate files such as extracted frames.
+func (gc *GenerateConfiguration) WorkDirectory() string {
+	return gc.workDirectory
+}
+
+// ProbeBackend is the name of the video.Prober used to read video metadata
+func (gc *GenerateConfiguration) ProbeBackend() string {
+	return gc.probeBackend
 }
 
 // PredictorPath points to the ml.Predictor model used to predict intertitles
 func (gc *GenerateConfiguration) PredictorPath() string {
-	return path.Join(intertitle.PredictorPath, intertitle.PredictorFilename)
+	return gc.predictorPath
+}
+
+// Smoothing is the name of the sequence.Smoother used to smooth over
+//  mispredicted frames
+func (gc *GenerateConfiguration) Smoothing() string {
+	return gc.smoothing
+}
+
+// SmoothingClosingThreshold defines the upper bound (in seconds) for a gap in
+//  intertitles to be closed
+func (gc *GenerateConfiguration) SmoothingClosingThreshold() float64 {
+	return gc.closingThreshold
+}
+
+// SmoothingOpeningThreshold defines the minimum length (in seconds) of an
+//  intertitle to be kept
+func (gc *GenerateConfiguration) SmoothingOpeningThreshold() float64 {
+	return gc.openingThreshold
+}
+
+// SnapTolerance is how far (in seconds) the start or end of an intertitle may
+//  be moved to a cut or fade
+func (gc *GenerateConfiguration) SnapTolerance() float64 {
+	return gc.snapTolerance
+}
+
+// ConfidenceHigh is the confidence (from 0 to 1) above which a frame starts
+//  an intertitle
+func (gc *GenerateConfiguration) ConfidenceHigh() float64 {
+	return gc.confidenceHigh
 }
 
-// SmoothingClosingThreshold defines the upper bound for a gap in intertitles
-//  to be closed
-func (gc *GenerateConfiguration) SmoothingClosingThreshold() uint {
-	return 15
+// ConfidenceLow is the confidence (from 0 to 1) above which a frame extends
+//  an adjacent intertitle
+func (gc *GenerateConfiguration) ConfidenceLow() float64 {
+	return gc.confidenceLow
 }
 
-// SmoothingOpeningThreshold defines the minimum length of an intertitle to be
-//  kept
-func (gc *GenerateConfiguration) SmoothingOpeningThreshold() uint {
-	return 15
+// ReviewBelow is the mean confidence (from 0 to 1) below which an intertitle
+//  is flagged for review
+func (gc *GenerateConfiguration) ReviewBelow() float64 {
+	return gc.reviewBelow
 }
 
 // FontName is the name of the font to use in the generated ASS
 func (gc *GenerateConfiguration) FontName() string {
-	return "Tryst"
+	return gc.style.FontName
 }
 
 // FontSize is the size of the font to use in the generated ASS
 func (gc *GenerateConfiguration) FontSize() uint {
-	return 48
+	return gc.style.FontSize
+}
+
+// Outline is the thickness of the box drawn around text in the generated ASS
+func (gc *GenerateConfiguration) Outline() uint {
+	return gc.style.Outline
+}
+
+// Alignment is the position of text in the generated ASS, as on a numeric
+//  keypad
+func (gc *GenerateConfiguration) Alignment() uint {
+	return gc.style.Alignment
+}
+
+// MarginLeft is the left margin of text in the generated ASS
+func (gc *GenerateConfiguration) MarginLeft() uint {
+	return gc.style.MarginLeft
+}
+
+// MarginRight is the right margin of text in the generated ASS
+func (gc *GenerateConfiguration) MarginRight() uint {
+	return gc.style.MarginRight
+}
+
+// MarginVertical is the vertical margin of text in the generated ASS
+func (gc *GenerateConfiguration) MarginVertical() uint {
+	return gc.style.MarginVertical
+}
+
+// CorrectionsPath is the YAML file of hand corrections to apply to the
+//  detected intertitle ranges, or empty if there are none
+func (gc *GenerateConfiguration) CorrectionsPath() string {
+	return gc.correctionsPath
+}
+
+func validateRanges(ranges string) error {
+	if ranges == "" {
+		return nil
+	}
+	_, err := read.ForPath(ranges)
+	return err
 }
 
-func defaultASS(srt *string) *string {
-	base := path.Base(*srt)
-	ext := path.Ext(*srt)
+func defaultASS(subs *string) *string {
+	base := path.Base(*subs)
+	ext := path.Ext(*subs)
 	base = base[:len(base)-len(ext)]
 	base += ".cabiria"
-	dir := path.Dir(*srt)
+	dir := path.Dir(*subs)
 	ass := path.Join(dir, base+".ass")
 	return &ass
 }
@@ -0,0 +1,117 @@
+package input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/liampulles/cabiria/cmd/internal/options"
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
+)
+
+// project holds the options which may be kept in a per-project YAML config
+//  file (see README). Relative paths are resolved against the directory of
+//  the file.
+type project struct {
+	Predictor     string            `yaml:"predictor"`
+	WorkDirectory string            `yaml:"workdir"`
+	Smoothing     projectSmoothing  `yaml:"smoothing"`
+	Confidence    projectConfidence `yaml:"confidence"`
+	Font          projectFont       `yaml:"font"`
+	Style         projectStyle      `yaml:"style"`
+}
+
+type projectSmoothing struct {
+	Method  string  `yaml:"method"`
+	Closing float64 `yaml:"closing"`
+	Opening float64 `yaml:"opening"`
+	Snap    float64 `yaml:"snap"`
+}
+
+type projectConfidence struct {
+	High   float64 `yaml:"high"`
+	Low    float64 `yaml:"low"`
+	Review float64 `yaml:"review"`
+}
+
+type projectFont struct {
+	Name string `yaml:"name"`
+	Size uint   `yaml:"size"`
+}
+
+type projectStyle struct {
+	Outline   uint           `yaml:"outline"`
+	Alignment uint           `yaml:"alignment"`
+	Margins   projectMargins `yaml:"margins"`
+}
+
+type projectMargins struct {
+	Left     uint `yaml:"left"`
+	Right    uint `yaml:"right"`
+	Vertical uint `yaml:"vertical"`
+}
+
+// defaultProject holds the options used if neither a config file nor a flag
+//  sets them.
+func defaultProject() project {
+	defaultStyle := style.Default("Tryst", 48)
+	return project{
+		Predictor:     path.Join(intertitle.PredictorPath, intertitle.PredictorFilename),
+		WorkDirectory: os.TempDir(),
+		Smoothing: projectSmoothing{
+			Method:  options.DefaultSmoothing,
+			Closing: options.DefaultClosing,
+			Opening: options.DefaultOpening,
+			Snap:    options.DefaultSnap,
+		},
+		Confidence: projectConfidence{
+			High:   options.DefaultConfidenceHigh,
+			Low:    options.DefaultConfidenceLow,
+			Review: options.DefaultReviewBelow,
+		},
+		Font: projectFont{
+			Name: defaultStyle.FontName,
+			Size: defaultStyle.FontSize,
+		},
+		Style: projectStyle{
+			Outline:   defaultStyle.Outline,
+			Alignment: defaultStyle.Alignment,
+			Margins: projectMargins{
+				Left:     defaultStyle.MarginLeft,
+				Right:    defaultStyle.MarginRight,
+				Vertical: defaultStyle.MarginVertical,
+			},
+		},
+	}
+}
+
+// loadProject reads a project config file. Options which the file does not
+//  set keep their default.
+func loadProject(filePath string) (project, error) {
+	result := defaultProject()
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return project{}, fmt.Errorf("could not read config file: %v", err)
+	}
+	err = yaml.UnmarshalStrict(data, &result)
+	if err != nil {
+		return project{}, fmt.Errorf("could not parse config file %s: %v", filePath, err)
+	}
+
+	dir := filepath.Dir(filePath)
+	result.Predictor = resolvePath(dir, result.Predictor)
+	result.WorkDirectory = resolvePath(dir, result.WorkDirectory)
+	return result, nil
+}
+
+func resolvePath(dir, filePath string) string {
+	if filePath == "" || filepath.IsAbs(filePath) {
+		return filePath
+	}
+	return filepath.Join(dir, filePath)
+}
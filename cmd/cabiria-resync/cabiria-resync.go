@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/liampulles/cabiria/cmd/cabiria-resync/core"
+)
+
+func main() {
+	core.Run(os.Args)
+}
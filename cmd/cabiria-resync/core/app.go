@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/liampulles/cabiria/cmd/cabiria-resync/input"
+
+	generate "github.com/liampulles/cabiria/cmd/cabiria-generate/core"
+	"github.com/liampulles/cabiria/pkg/log"
+	"github.com/liampulles/cabiria/pkg/progress"
+)
+
+// Run runs the main app for cabiria-resync
+func Run(args []string) {
+	config, err := input.GetResyncConfiguration(args)
+	failIf(err)
+	if config.Quiet() {
+		logger = log.New(os.Stderr, config.LogLevel())
+		reporter = progress.Quiet{}
+	} else {
+		// Log messages are written above the progress line, which is shared
+		//  with the generate stages
+		terminal := progress.NewTerminal(os.Stderr)
+		logger = log.New(terminal.Writer(), config.LogLevel())
+		reporter = terminal
+	}
+	generate.SetLogger(logger)
+	generate.SetReporter(reporter)
+	ctx, stop := generate.InterruptibleContext()
+	defer stop()
+	workspace, err := generate.NewWorkspace(config.WorkDirectory())
+	failIf(err)
+	err = resync(ctx, &config, workspace)
+	// Cleanup regardless of the outcome
+	cleanupErr := workspace.Remove()
+	failIf(err)
+	failIf(cleanupErr)
+}
+
+func resync(ctx context.Context, config *input.ResyncConfiguration, workspace generate.Workspace) error {
+	videoInfo, err := generate.ExtractVideoInformation(ctx, config, workspace)
+	if err != nil {
+		return err
+	}
+	videoInfo, err = generate.ApplyCorrections(ctx, videoInfo, config)
+	if err != nil {
+		return err
+	}
+	err = generate.SaveRanges(ctx, videoInfo, config)
+	if err != nil {
+		return err
+	}
+	subsInfo, err := generate.ExtractSubtitlesInformation(ctx, config)
+	if err != nil {
+		return err
+	}
+	resynced := ResyncSubtitles(videoInfo, subsInfo)
+	// Don't leave a partial SRT behind if interrupted
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return SaveSRT(resynced, config)
+}
+
+// logger receives log messages, which are written to stderr.
+var logger = log.New(os.Stderr, log.Info)
+
+func failIf(err error) {
+	if errors.Is(err, context.Canceled) {
+		logger.Error("Stopped, since cabiria was interrupted")
+		os.Exit(1)
+	}
+	if err != nil {
+		logger.Error("Encountered fatal error", "error", err)
+		os.Exit(1)
+	}
+}
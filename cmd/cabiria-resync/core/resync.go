@@ -0,0 +1,51 @@
+package core
+
+import (
+	"fmt"
+	"os"
+
+	generate "github.com/liampulles/cabiria/cmd/cabiria-generate/core"
+	"github.com/liampulles/cabiria/pkg/progress"
+	"github.com/liampulles/cabiria/pkg/subtitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/write"
+)
+
+// Stages of resyncing, as reported to the progress reporter
+const (
+	stageResync  = "Resyncing subtitles"
+	stageSaveSRT = "Saving SRT"
+)
+
+// reporter is told of the progress of each stage.
+var reporter progress.Reporter = progress.NewTerminal(os.Stderr)
+
+// OutputConfiguration provides configuration options necessary to save
+//  the resynced subtitles.
+type OutputConfiguration interface {
+	OutPath() string
+}
+
+// ResyncSubtitles retimes the extracted subtitles such that they align with
+//  the detected intertitles. No styling is applied.
+func ResyncSubtitles(
+	videoInfo generate.VideoInformation,
+	subInfo generate.SubtitlesInformation) []subtitle.Subtitle {
+	reporter.Start(stageResync, 0)
+
+	// Correct sub timing slice to intertitles
+	resynced := subtitle.AlignSubtitles(subInfo.Subtitles, videoInfo.IntertitleRanges)
+
+	reporter.Finish(stageResync)
+	return resynced
+}
+
+// SaveSRT writes resynced subtitles to disk, in SRT format.
+func SaveSRT(subs []subtitle.Subtitle, config OutputConfiguration) error {
+	reporter.Start(stageSaveSRT, 0)
+	if err := write.SRT(subs, config.OutPath()); err != nil {
+		return fmt.Errorf("could not save SRT to %s: %v", config.OutPath(), err)
+	}
+	reporter.Finish(stageSaveSRT)
+	logger.Info("Saved SRT", "path", config.OutPath())
+	return nil
+}
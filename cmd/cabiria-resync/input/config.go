@@ -0,0 +1,216 @@
+package input
+
+import (
+	"flag"
+	"fmt"
+	"path"
+
+	"github.com/liampulles/cabiria/cmd/internal/options"
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/log"
+)
+
+// ResyncConfiguration provides configuration options necessary
+//  for resyncing an input subtitle to the intertitles of an input video
+type ResyncConfiguration struct {
+	videoPath        string
+	subtitlesPath    string
+	encoding         string
+	outPath          string
+	workDirectory    string
+	probeBackend     string
+	smoothing        string
+	closingThreshold float64
+	openingThreshold float64
+	confidenceHigh   float64
+	confidenceLow    float64
+	reviewBelow      float64
+	snapTolerance    float64
+	quiet            bool
+	logLevel         log.Level
+	rangesOutPath    string
+	correctionsPath  string
+}
+
+// GetResyncConfiguration parses the command line to provide config
+//  for the core application
+func GetResyncConfiguration(args []string) (ResyncConfiguration, error) {
+	common := options.AddCommonFlags(flag.CommandLine, "Subtitles to resync")
+	out := flag.String("out", "", "(Optional) SRT file to save to. Default is the subtitles path with .cabiria.srt extension.")
+
+	flag.CommandLine.Parse(args[1:])
+
+	if common.Video == "" {
+		return ResyncConfiguration{}, fmt.Errorf("you must provide a -video parameter")
+	}
+	subsPath, err := options.MergeAlias("-subs", common.Subs, "-srt", common.SRT)
+	if err != nil {
+		return ResyncConfiguration{}, err
+	}
+	if subsPath == "" {
+		return ResyncConfiguration{}, fmt.Errorf("you must provide a -subs parameter")
+	}
+	if err := options.ValidateSubtitles(subsPath); err != nil {
+		return ResyncConfiguration{}, err
+	}
+	subsEncoding, err := options.CanonicalEncoding(common.Encoding)
+	if err != nil {
+		return ResyncConfiguration{}, err
+	}
+	if *out == "" {
+		out = defaultOut(&subsPath)
+	}
+
+	if common.WorkDirectory == "" {
+		return ResyncConfiguration{}, fmt.Errorf("the -workdir parameter may not be empty")
+	}
+	if err := options.ValidateProbeBackend(common.Probe); err != nil {
+		return ResyncConfiguration{}, err
+	}
+	err = options.ValidateDetection(common.Smoothing, common.Closing, common.Opening, common.Snap,
+		common.ConfidenceLow, common.ConfidenceHigh, common.ReviewBelow)
+	if err != nil {
+		return ResyncConfiguration{}, err
+	}
+	level, err := log.ParseLevel(common.LogLevel)
+	if err != nil {
+		return ResyncConfiguration{}, err
+	}
+	if err := options.ValidateCorrections(common.Corrections); err != nil {
+		return ResyncConfiguration{}, err
+	}
+	if err := options.ValidateRangesOut(common.RangesOut); err != nil {
+		return ResyncConfiguration{}, err
+	}
+
+	return ResyncConfiguration{
+		videoPath:        common.Video,
+		subtitlesPath:    subsPath,
+		encoding:         subsEncoding,
+		outPath:          *out,
+		workDirectory:    common.WorkDirectory,
+		probeBackend:     common.Probe,
+		smoothing:        common.Smoothing,
+		closingThreshold: common.Closing,
+		openingThreshold: common.Opening,
+		confidenceHigh:   common.ConfidenceHigh,
+		confidenceLow:    common.ConfidenceLow,
+		reviewBelow:      common.ReviewBelow,
+		snapTolerance:    common.Snap,
+		quiet:            common.Quiet,
+		logLevel:         level,
+		rangesOutPath:    common.RangesOut,
+		correctionsPath:  common.Corrections,
+	}, nil
+}
+
+// VideoPath is the path to the input video
+func (rc *ResyncConfiguration) VideoPath() string {
+	return rc.videoPath
+}
+
+// SubtitlesPath is the path of the input subtitles
+func (rc *ResyncConfiguration) SubtitlesPath() string {
+	return rc.subtitlesPath
+}
+
+// SubtitlesEncoding is the character encoding of the input subtitles, or
+//  empty if it should be detected
+func (rc *ResyncConfiguration) SubtitlesEncoding() string {
+	return rc.encoding
+}
+
+// OutPath is the path of the output subtitle
+func (rc *ResyncConfiguration) OutPath() string {
+	return rc.outPath
+}
+
+// WorkDirectory is the directory in which a temporary directory is created
+//  for each run, to hold intermediate files such as extracted frames.
+func (rc *ResyncConfiguration) WorkDirectory() string {
+	return rc.workDirectory
+}
+
+// ProbeBackend is the name of the video.Prober used to read video metadata
+func (rc *ResyncConfiguration) ProbeBackend() string {
+	return rc.probeBackend
+}
+
+// PredictorPath points to the ml.Predictor model used to predict intertitles
+func (rc *ResyncConfiguration) PredictorPath() string {
+	return path.Join(intertitle.PredictorPath, intertitle.PredictorFilename)
+}
+
+// Smoothing is the name of the sequence.Smoother used to smooth over
+//  mispredicted frames
+func (rc *ResyncConfiguration) Smoothing() string {
+	return rc.smoothing
+}
+
+// SmoothingClosingThreshold defines the upper bound (in seconds) for a gap in
+//  intertitles to be closed
+func (rc *ResyncConfiguration) SmoothingClosingThreshold() float64 {
+	return rc.closingThreshold
+}
+
+// SmoothingOpeningThreshold defines the minimum length (in seconds) of an
+//  intertitle to be kept
+func (rc *ResyncConfiguration) SmoothingOpeningThreshold() float64 {
+	return rc.openingThreshold
+}
+
+// SnapTolerance is how far (in seconds) the start or end of an intertitle may
+//  be moved to a cut or fade
+func (rc *ResyncConfiguration) SnapTolerance() float64 {
+	return rc.snapTolerance
+}
+
+// ConfidenceHigh is the confidence (from 0 to 1) above which a frame starts
+//  an intertitle
+func (rc *ResyncConfiguration) ConfidenceHigh() float64 {
+	return rc.confidenceHigh
+}
+
+// ConfidenceLow is the confidence (from 0 to 1) above which a frame extends
+//  an adjacent intertitle
+func (rc *ResyncConfiguration) ConfidenceLow() float64 {
+	return rc.confidenceLow
+}
+
+// ReviewBelow is the mean confidence (from 0 to 1) below which an intertitle
+//  is flagged for review
+func (rc *ResyncConfiguration) ReviewBelow() float64 {
+	return rc.reviewBelow
+}
+
+// Quiet is true if progress should not be reported
+func (rc *ResyncConfiguration) Quiet() bool {
+	return rc.quiet
+}
+
+// LogLevel is the least severe level of log messages to write
+func (rc *ResyncConfiguration) LogLevel() log.Level {
+	return rc.logLevel
+}
+
+// RangesOutPath is where to save the detected intertitle ranges, or empty if
+//  they should not be saved
+func (rc *ResyncConfiguration) RangesOutPath() string {
+	return rc.rangesOutPath
+}
+
+// CorrectionsPath is the YAML file of hand corrections to apply to the
+//  detected intertitle ranges, or empty if there are none
+func (rc *ResyncConfiguration) CorrectionsPath() string {
+	return rc.correctionsPath
+}
+
+func defaultOut(subs *string) *string {
+	base := path.Base(*subs)
+	ext := path.Ext(*subs)
+	base = base[:len(base)-len(ext)]
+	base += ".cabiria"
+	dir := path.Dir(*subs)
+	out := path.Join(dir, base+".srt")
+	return &out
+}
@@ -0,0 +1,187 @@
+// Package options holds the command line options which cabiria-generate and
+//  cabiria-resync have in common, along with their defaults and validation,
+//  so that the two commands stay alike.
+package options
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	"github.com/liampulles/cabiria/pkg/intertitle/correct"
+	"github.com/liampulles/cabiria/pkg/intertitle/write"
+	"github.com/liampulles/cabiria/pkg/meta"
+	"github.com/liampulles/cabiria/pkg/sequence"
+	"github.com/liampulles/cabiria/pkg/subtitle/read"
+	"github.com/liampulles/cabiria/pkg/video"
+)
+
+// Defaults of the options, used unless a flag (or config file) sets them.
+const (
+	DefaultProbe          = "ffprobe"
+	DefaultSmoothing      = sequence.MorphologicalName
+	DefaultClosing        = 0.625
+	DefaultOpening        = 0.625
+	DefaultSnap           = 0.2
+	DefaultConfidenceHigh = 0.5
+	DefaultConfidenceLow  = 0.5
+	DefaultReviewBelow    = 0.75
+	DefaultLogLevel       = "info"
+)
+
+// Common holds the values of the flags which both commands take.
+type Common struct {
+	Video          string
+	Subs           string
+	SRT            string
+	Encoding       string
+	WorkDirectory  string
+	Probe          string
+	Smoothing      string
+	Closing        float64
+	Opening        float64
+	Snap           float64
+	ConfidenceHigh float64
+	ConfidenceLow  float64
+	ReviewBelow    float64
+	Quiet          bool
+	LogLevel       string
+	Corrections    string
+	RangesOut      string
+}
+
+// AddCommonFlags defines the flags which both commands take on flags, with
+//  subsUsage describing what the subtitles are for. Their values are set in
+//  the result once flags is parsed.
+func AddCommonFlags(flags *flag.FlagSet, subsUsage string) *Common {
+	var c Common
+	flags.StringVar(&c.Video, "video", "", "Silent film to analyze for intertitles.")
+	flags.StringVar(&c.Subs, "subs", "", subsUsage+": SRT, WebVTT (.vtt), ASS/SSA or SubViewer (.sub).")
+	flags.StringVar(&c.SRT, "srt", "", "(Deprecated) Same as -subs.")
+	flags.StringVar(&c.Encoding, "encoding", "", "(Optional) Character encoding of the subtitles: utf-8, utf-16, utf-16le, utf-16be, iso-8859-1, windows-1252 or windows-1251. Default is to detect it.")
+	flags.StringVar(&c.WorkDirectory, "workdir", os.TempDir(), "(Optional) Directory in which to create a temporary directory for intermediate files.")
+	flags.StringVar(&c.Probe, "probe", DefaultProbe, "(Optional) Backend used to read video metadata: ffprobe or mediainfo.")
+	flags.StringVar(&c.Smoothing, "smoothing", DefaultSmoothing, "(Optional) How to smooth over mispredicted frames: morphological (fill gaps shorter than -closing, then drop intertitles shorter than -opening) or hmm (find the most likely intertitles with a hidden Markov model, smoothing over runs shorter than the larger of -closing and -opening).")
+	flags.Float64Var(&c.Closing, "closing", DefaultClosing, "(Optional) Gaps in an intertitle shorter than this many seconds are closed.")
+	flags.Float64Var(&c.Opening, "opening", DefaultOpening, "(Optional) Intertitles shorter than this many seconds are discarded.")
+	flags.Float64Var(&c.Snap, "snap", DefaultSnap, "(Optional) Snap the start and end of an intertitle to a cut or fade within this many seconds. 0 disables snapping.")
+	flags.Float64Var(&c.ConfidenceHigh, "confidence-high", DefaultConfidenceHigh, "(Optional) Confidence (0 to 1) that a frame is an intertitle above which it starts an intertitle.")
+	flags.Float64Var(&c.ConfidenceLow, "confidence-low", DefaultConfidenceLow, "(Optional) Confidence (0 to 1) that a frame is an intertitle above which it extends an adjacent intertitle. Set below -confidence-high to ignore flickering predictions.")
+	flags.Float64Var(&c.ReviewBelow, "review-below", DefaultReviewBelow, "(Optional) Intertitles with a mean confidence (0 to 1) below this are logged for review.")
+	flags.BoolVar(&c.Quiet, "quiet", false, "(Optional) Don't report progress, e.g. when run from a script.")
+	flags.StringVar(&c.LogLevel, "loglevel", DefaultLogLevel, "(Optional) Least severe log messages to write to stderr: debug, info, warn or error.")
+	flags.StringVar(&c.Corrections, "corrections", "", "(Optional) YAML file of hand corrections (add, delete, adjust or restyle intertitles) to apply to the detected intertitles.")
+	flags.StringVar(&c.RangesOut, "ranges-out", "", "(Optional) File to save the detected intertitle ranges to, after any corrections. The extension gives the format: .json, .csv, .ffmetadata (FFmpeg chapters) or .xml (Matroska chapters).")
+
+	// Custom usage message
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "%s %s (%s)\n\nUsage:\n", meta.ProgramName, meta.ProgramVersion, meta.ProgramURL)
+		flags.PrintDefaults()
+	}
+	return &c
+}
+
+// ValidateProbeBackend checks that backend names a video.Prober.
+func ValidateProbeBackend(backend string) error {
+	_, err := video.NewProber(backend)
+	return err
+}
+
+// ValidateSubtitles checks that the format of the subtitles is known, so
+//  that it is not found out after the video is analysed.
+func ValidateSubtitles(subs string) error {
+	_, err := read.ForPath(subs)
+	return err
+}
+
+// CanonicalEncoding checks that the subtitle encoding is known, if given,
+//  and returns its canonical name (see file.CanonicalEncoding).
+func CanonicalEncoding(encoding string) (string, error) {
+	if encoding == "" {
+		return "", nil
+	}
+	return file.CanonicalEncoding(encoding)
+}
+
+// MergeAlias returns the value of an option which may also be given by its
+//  alias (e.g. an old name). Giving both with different values is an error.
+func MergeAlias(name, value, alias, aliasValue string) (string, error) {
+	if value != "" && aliasValue != "" && value != aliasValue {
+		return "", fmt.Errorf("%s and %s are the same option, so only one may be given", name, alias)
+	}
+	if value != "" {
+		return value, nil
+	}
+	return aliasValue, nil
+}
+
+// ValidateCorrections loads the corrections, so that mistakes in them are
+//  found before the video is analysed.
+func ValidateCorrections(corrections string) error {
+	if corrections == "" {
+		return nil
+	}
+	_, err := correct.Load(corrections)
+	return err
+}
+
+// ValidateRangesOut checks that the format of the ranges file to save is
+//  known, if one is given.
+func ValidateRangesOut(rangesOut string) error {
+	if rangesOut == "" {
+		return nil
+	}
+	_, err := write.ForPath(rangesOut)
+	return err
+}
+
+// validateSmoothing checks that smoothing names a sequence.Smoother.
+func validateSmoothing(smoothing string) error {
+	_, err := sequence.NewSmoother(smoothing, sequence.Options{})
+	return err
+}
+
+// validateConfidence checks that the confidences are between 0 and 1, and
+//  that low is not more than high.
+func validateConfidence(low, high, review float64) error {
+	for _, elem := range []struct {
+		name  string
+		value float64
+	}{{"confidence-low", low}, {"confidence-high", high}, {"review-below", review}} {
+		if math.IsNaN(elem.value) || elem.value < 0.0 || elem.value > 1.0 {
+			return fmt.Errorf("the -%s parameter must be between 0 and 1", elem.name)
+		}
+	}
+	if low > high {
+		return fmt.Errorf("the -confidence-low parameter must not be more than -confidence-high")
+	}
+	return nil
+}
+
+// validateThreshold checks that the option called name is a non-negative
+//  number of seconds.
+func validateThreshold(name string, seconds float64) error {
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds < 0.0 {
+		return fmt.Errorf("the -%s parameter must be a non-negative number of seconds", name)
+	}
+	return nil
+}
+
+// ValidateDetection checks the options which control how intertitles are
+//  detected.
+func ValidateDetection(smoothing string, closing, opening, snap, confidenceLow, confidenceHigh, reviewBelow float64) error {
+	if err := validateSmoothing(smoothing); err != nil {
+		return err
+	}
+	for _, elem := range []struct {
+		name    string
+		seconds float64
+	}{{"closing", closing}, {"opening", opening}, {"snap", snap}} {
+		if err := validateThreshold(elem.name, elem.seconds); err != nil {
+			return err
+		}
+	}
+	return validateConfidence(confidenceLow, confidenceHigh, reviewBelow)
+}
@@ -1,8 +1,10 @@
 module github.com/liampulles/cabiria
 
-go 1.13
+go 1.17
 
 require (
 	github.com/jinzhu/copier v0.0.0-20190924061706-b57f9002281a
 	github.com/lucasb-eyer/go-colorful v1.0.3
+	golang.org/x/text v0.13.0
+	gopkg.in/yaml.v2 v2.4.0
 )
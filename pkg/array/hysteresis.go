@@ -0,0 +1,30 @@
+package array
+
+// HysteresisThreshold maps confidences to booleans using two thresholds, so
+//  that noise around a single threshold does not make items flicker on and
+//  off. An item is on if its confidence is above high, or if it is above low
+//  and part of a run of such items which includes one above high.
+//  e.g. low 0.3, high 0.7: [0.2, 0.5, 0.8, 0.4, 0.2, 0.5] -> [0, 1, 1, 1, 0, 0]
+func HysteresisThreshold(confidences []float64, low, high float64) []bool {
+	result := make([]bool, len(confidences))
+	runStart := -1
+	runIsOn := false
+	for i := 0; i <= len(confidences); i++ {
+		// Treat the end as below low, to close off the last run
+		if i < len(confidences) && confidences[i] > low {
+			if runStart < 0 {
+				runStart = i
+				runIsOn = false
+			}
+			runIsOn = runIsOn || confidences[i] > high
+			continue
+		}
+		if runStart >= 0 && runIsOn {
+			for j := runStart; j < i; j++ {
+				result[j] = true
+			}
+		}
+		runStart = -1
+	}
+	return result
+}
@@ -0,0 +1,219 @@
+package file
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Character encodings understood by Decode
+const (
+	UTF8        = "utf-8"
+	UTF16       = "utf-16"
+	UTF16LE     = "utf-16le"
+	UTF16BE     = "utf-16be"
+	ISO88591    = "iso-8859-1"
+	Windows1252 = "windows-1252"
+	Windows1251 = "windows-1251"
+)
+
+// Encodings are the names of the character encodings understood by Decode.
+//  The byte order of UTF-16 is given by its byte order mark, or is big endian
+//  without one (see RFC 2781).
+var Encodings = []string{UTF8, UTF16, UTF16LE, UTF16BE, ISO88591, Windows1252, Windows1251}
+
+// encodingAliases maps other common names of encodings to their name in
+//  Encodings.
+var encodingAliases = map[string]string{
+	"utf8":    UTF8,
+	"utf16":   UTF16,
+	"latin1":  ISO88591,
+	"latin-1": ISO88591,
+	"cp1252":  Windows1252,
+	"cp1251":  Windows1251,
+}
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// CanonicalEncoding gives the name in Encodings of the encoding called name,
+//  ignoring case and allowing common aliases (e.g. "latin1" or "cp1251").
+func CanonicalEncoding(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := encodingAliases[name]; ok {
+		return alias, nil
+	}
+	for _, elem := range Encodings {
+		if name == elem {
+			return elem, nil
+		}
+	}
+	return "", fmt.Errorf("unknown encoding %q. Valid encodings are: %s", name, strings.Join(Encodings, ", "))
+}
+
+// DetectEncoding guesses the character encoding of text from its byte order
+//  mark, or failing that from its bytes. Text which is valid UTF-8 is taken
+//  to be UTF-8. Otherwise it is taken to be a single byte encoding:
+//  Windows-1251 if most letters are in words written wholly outside ASCII
+//  (as Cyrillic is), else Windows-1252 (a superset of ISO-8859-1 in
+//  practice). Accented Latin letters sit among ASCII letters (e.g. "déjà"),
+//  so they count towards Windows-1252 however many there are.
+func DetectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return UTF8
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return UTF16LE
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return UTF16BE
+	}
+
+	// Mostly ASCII text in UTF-16 has a zero in every other byte
+	var evenZeros, oddZeros int
+	for i, elem := range data {
+		if elem == 0 {
+			if i%2 == 0 {
+				evenZeros++
+			} else {
+				oddZeros++
+			}
+		}
+	}
+	if len(data) >= 2 {
+		half := len(data) / 2
+		if oddZeros > half/2 && evenZeros <= half/10 {
+			return UTF16LE
+		}
+		if evenZeros > half/2 && oddZeros <= half/10 {
+			return UTF16BE
+		}
+	}
+
+	if utf8.Valid(data) {
+		return UTF8
+	}
+	if cyrillic, latin := countLetters(data); cyrillic > latin {
+		return Windows1251
+	}
+	return Windows1252
+}
+
+// countLetters counts the letters in words of only non-ASCII letters (as
+//  Cyrillic words are in Windows-1251), and in words with any ASCII letter.
+func countLetters(data []byte) (cyrillic, latin int) {
+	var length int
+	var ascii bool
+	endWord := func() {
+		if ascii {
+			latin += length
+		} else {
+			cyrillic += length
+		}
+		length, ascii = 0, false
+	}
+	for _, elem := range data {
+		if !isLetterByte(elem) {
+			endWord()
+			continue
+		}
+		length++
+		ascii = ascii || elem < 0x80
+	}
+	endWord()
+	return cyrillic, latin
+}
+
+// isLetterByte returns true if b is an ASCII letter, or a letter in both
+//  ISO-8859-1 and Windows-1251 (from 0xC0, besides × and ÷ in ISO-8859-1),
+//  or Ё or ё in Windows-1251. Otherwise false.
+func isLetterByte(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b >= 0xC0 || b == 0xA8 || b == 0xB8
+}
+
+// Decode converts text in encoding (see Encodings) to UTF-8, removing any
+//  byte order mark.
+func Decode(data []byte, encoding string) (string, error) {
+	encoding, err := CanonicalEncoding(encoding)
+	if err != nil {
+		return "", err
+	}
+	switch encoding {
+	case UTF8:
+		data = bytes.TrimPrefix(data, utf8BOM)
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("the text is not valid UTF-8, so give its encoding")
+		}
+		return string(data), nil
+	case UTF16:
+		if bytes.HasPrefix(data, utf16LEBOM) {
+			return decodeUTF16(bytes.TrimPrefix(data, utf16LEBOM), binary.LittleEndian)
+		}
+		return decodeUTF16(bytes.TrimPrefix(data, utf16BEBOM), binary.BigEndian)
+	case UTF16LE:
+		return decodeUTF16(bytes.TrimPrefix(data, utf16LEBOM), binary.LittleEndian)
+	case UTF16BE:
+		return decodeUTF16(bytes.TrimPrefix(data, utf16BEBOM), binary.BigEndian)
+	case ISO88591:
+		return charmap.ISO8859_1.NewDecoder().String(string(data))
+	case Windows1252:
+		return charmap.Windows1252.NewDecoder().String(string(data))
+	default:
+		return charmap.Windows1251.NewDecoder().String(string(data))
+	}
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) (string, error) {
+	if len(data)%2 != 0 {
+		return "", fmt.Errorf("the text is not valid UTF-16, since it has an odd number of bytes")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// DetectFileEncoding guesses the character encoding of the text file at path
+//  (see DetectEncoding).
+func DetectFileEncoding(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return DetectEncoding(data), nil
+}
+
+// ReadLinesFromEncodedTextFile is like ReadLinesFromTextFile, but first
+//  converts the text from encoding (see Encodings) to UTF-8. If encoding is
+//  empty, it is detected (see DetectEncoding).
+func ReadLinesFromEncodedTextFile(path string, encoding string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if encoding == "" {
+		encoding = DetectEncoding(data)
+	}
+	text, err := Decode(data, encoding)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s as %s: %v", path, encoding, err)
+	}
+
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, elem := range lines {
+		lines[i] = strings.TrimSuffix(elem, "\r")
+	}
+	return lines, nil
+}
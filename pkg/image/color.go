@@ -1,6 +1,8 @@
 package image
 
 import (
+	"context"
+	"fmt"
 	"image"
 	"image/color"
 
@@ -24,11 +26,41 @@ func ChangeValue(col color.Color, value float64) color.Color {
 	return colorful.Hsv(h, s, clamped)
 }
 
+// HexColor formats the color as e.g. "#ff8000", ignoring alpha. A nil color
+//  gives an empty string.
+func HexColor(col color.Color) string {
+	if col == nil {
+		return ""
+	}
+	rgba := color.RGBAModel.Convert(col).(color.RGBA)
+	return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+}
+
+// ParseHexColor parses a color formatted by HexColor, e.g. "#ff8000". An
+//  empty string gives a nil color.
+func ParseHexColor(hex string) (color.Color, error) {
+	if hex == "" {
+		return nil, nil
+	}
+	var r, g, b uint8
+	n, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b)
+	if err != nil || n != 3 || len(hex) != 7 {
+		return nil, fmt.Errorf("%q is not a color of the form #rrggbb", hex)
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 255}, nil
+}
+
 // GetForegroundAndBackground guesses the foreground and background color.
 func GetForegroundAndBackground(img image.Image) (color.Color, color.Color, error) {
+	return GetForegroundAndBackgroundContext(context.Background(), img)
+}
+
+// GetForegroundAndBackgroundContext is like GetForegroundAndBackground, but
+//  gives up if ctx is done first.
+func GetForegroundAndBackgroundContext(ctx context.Context, img image.Image) (color.Color, color.Color, error) {
 	// Use KMeans to quantize image into two centroids.
 	kMeans := cluster.NewKMeansClassifier(2, 5000)
-	counts, _, err := kMeans.Fit(allPixelsAsDatum(img))
+	counts, _, err := kMeans.FitContext(ctx, allPixelsAsDatum(img))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -14,6 +14,7 @@ func GetPNG(filePath string) (image.Image, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
 	image, _, err := image.Decode(f)
 	return image, err
 }
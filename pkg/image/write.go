@@ -0,0 +1,17 @@
+package image
+
+import (
+	"image"
+	"image/png"
+	"os"
+)
+
+// SavePNG saves img to the disk at filePath, in PNG format.
+func SavePNG(filePath string, img image.Image) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
@@ -0,0 +1,276 @@
+package correct
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"math"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+// Action is what a Correction does.
+type Action string
+
+// Actions of a Correction
+const (
+	// Add adds a missed intertitle from Start to End, replacing any it
+	//  overlaps.
+	Add Action = "add"
+	// Delete removes the intertitle at At.
+	Delete Action = "delete"
+	// Adjust moves the Start and/or End of the intertitle at At, replacing
+	//  any other intertitles it then overlaps.
+	Adjust Action = "adjust"
+	// Restyle overrides the Foreground and/or Background of the intertitle
+	//  at At.
+	Restyle Action = "style"
+)
+
+// Correction is a hand fix to the detected intertitles. Intertitles are found
+//  by a time within them rather than by index or frame, so that a correction
+//  still applies when detection changes slightly (e.g. with a new model).
+//  Nil fields are left as they are.
+type Correction struct {
+	Action     Action
+	At         time.Duration
+	Start      *time.Duration
+	End        *time.Duration
+	Foreground color.Color
+	Background color.Color
+}
+
+// yamlCorrection is the YAML format of a Correction. Times are in seconds.
+type yamlCorrection struct {
+	Action     string   `yaml:"action"`
+	At         *float64 `yaml:"at"`
+	Start      *float64 `yaml:"start"`
+	End        *float64 `yaml:"end"`
+	Foreground string   `yaml:"foreground"`
+	Background string   `yaml:"background"`
+}
+
+// Load reads corrections from a YAML file, e.g.:
+//
+//    # Missed title card
+//    - action: add
+//      start: 62.5
+//      end: 66
+//    # Bright outdoor shot
+//    - action: delete
+//      at: 130.2
+//    - action: adjust
+//      at: 200
+//      end: 204.5
+//    - action: style
+//      at: 200
+//      foreground: "#f0e0c0"
+//
+//  Times are in seconds. An added intertitle is white on black unless
+//  colors are given.
+func Load(path string) ([]Correction, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var parsed []yamlCorrection
+	err = yaml.UnmarshalStrict(data, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse corrections in %s: %v", path, err)
+	}
+
+	result := make([]Correction, len(parsed))
+	for i, elem := range parsed {
+		correction, err := elem.toCorrection()
+		if err != nil {
+			return nil, fmt.Errorf("correction %d in %s is invalid: %v", i+1, path, err)
+		}
+		result[i] = correction
+	}
+	return result, nil
+}
+
+func (yc yamlCorrection) toCorrection() (Correction, error) {
+	foreground, err := cabiriaImage.ParseHexColor(yc.Foreground)
+	if err != nil {
+		return Correction{}, err
+	}
+	background, err := cabiriaImage.ParseHexColor(yc.Background)
+	if err != nil {
+		return Correction{}, err
+	}
+	for _, elem := range []*float64{yc.At, yc.Start, yc.End} {
+		if elem != nil && (math.IsNaN(*elem) || math.IsInf(*elem, 0) || *elem < 0.0) {
+			return Correction{}, fmt.Errorf("times must be a non-negative number of seconds")
+		}
+	}
+	result := Correction{
+		Action:     Action(yc.Action),
+		Start:      fromSeconds(yc.Start),
+		End:        fromSeconds(yc.End),
+		Foreground: foreground,
+		Background: background,
+	}
+	if yc.At != nil {
+		result.At = *fromSeconds(yc.At)
+	}
+
+	switch result.Action {
+	case Add:
+		if yc.At != nil {
+			return Correction{}, fmt.Errorf("an added intertitle is given by its start and end, not at")
+		}
+		if result.Start == nil || result.End == nil {
+			return Correction{}, fmt.Errorf("an added intertitle needs a start and an end")
+		}
+	case Delete:
+		if yc.At == nil {
+			return Correction{}, fmt.Errorf("a deletion needs the time of the intertitle to delete (at)")
+		}
+	case Adjust:
+		if yc.At == nil {
+			return Correction{}, fmt.Errorf("an adjustment needs the time of the intertitle to adjust (at)")
+		}
+		if result.Start == nil && result.End == nil {
+			return Correction{}, fmt.Errorf("an adjustment needs a new start and/or end")
+		}
+		// Keeping at within the new bounds means the correction can be
+		//  applied again (e.g. to ranges saved after correction).
+		if (result.Start != nil && result.At < *result.Start) || (result.End != nil && result.At > *result.End) {
+			return Correction{}, fmt.Errorf("at must be within the new start and end")
+		}
+	case Restyle:
+		if yc.At == nil {
+			return Correction{}, fmt.Errorf("a style override needs the time of the intertitle to restyle (at)")
+		}
+		if foreground == nil && background == nil {
+			return Correction{}, fmt.Errorf("a style override needs a foreground and/or background")
+		}
+	default:
+		return Correction{}, fmt.Errorf("unknown action %q. Valid actions are: %s, %s, %s, %s", yc.Action, Add, Delete, Adjust, Restyle)
+	}
+	if result.Start != nil && result.End != nil && *result.Start > *result.End {
+		return Correction{}, fmt.Errorf("the start must not be after the end")
+	}
+	return result, nil
+}
+
+// Apply applies corrections, in order, to ranges, and sorts the result by
+//  start. fps is the frame rate of the video, used to number the frames of
+//  added intertitles. The indices of corrections which could not be applied
+//  (because no intertitle is at their time, or the adjusted intertitle would
+//  end before it starts) are returned as skipped. ranges is not modified.
+func Apply(ranges []intertitle.Range, corrections []Correction, fps float64) (result []intertitle.Range, skipped []int) {
+	result = append([]intertitle.Range{}, ranges...)
+	for i, elem := range corrections {
+		var applied bool
+		result, applied = elem.apply(result, fps)
+		if !applied {
+			skipped = append(skipped, i)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Start().Before(result[j].Start())
+	})
+	return result, skipped
+}
+
+func (c Correction) apply(ranges []intertitle.Range, fps float64) ([]intertitle.Range, bool) {
+	if c.Action == Add {
+		added := newRange(*c.Start, *c.End, fps, intertitle.Style{
+			ForegroundColor: orDefault(c.Foreground, color.White),
+			BackgroundColor: orDefault(c.Background, color.Black),
+		})
+		return append(withoutOverlapping(ranges, added), added), true
+	}
+
+	index := indexAt(ranges, c.At)
+	if index < 0 {
+		return ranges, false
+	}
+	target := ranges[index]
+	switch c.Action {
+	case Delete:
+		return append(ranges[:index:index], ranges[index+1:]...), true
+	case Adjust:
+		start := cabiriaTime.ToDuration(target.Start())
+		if c.Start != nil {
+			start = *c.Start
+		}
+		end := cabiriaTime.ToDuration(target.End())
+		if c.End != nil {
+			end = *c.End
+		}
+		if start > end {
+			return ranges, false
+		}
+		adjusted := newRange(start, end, target.FPS, target.Style)
+		others := append(ranges[:index:index], ranges[index+1:]...)
+		return append(withoutOverlapping(others, adjusted), adjusted), true
+	case Restyle:
+		ranges[index].Style = intertitle.Style{
+			ForegroundColor: orDefault(c.Foreground, target.Style.ForegroundColor),
+			BackgroundColor: orDefault(c.Background, target.Style.BackgroundColor),
+		}
+	}
+	return ranges, true
+}
+
+// newRange creates a Timed range, numbering the frames with fps.
+func newRange(start, end time.Duration, fps float64, style intertitle.Style) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: frameAt(start, fps),
+		EndFrame:   frameAt(end, fps),
+		FPS:        fps,
+		Timed:      true,
+		StartTime:  start,
+		EndTime:    end,
+		Style:      style,
+	}
+}
+
+// indexAt finds the first range which at is within, or -1 if none is.
+func indexAt(ranges []intertitle.Range, at time.Duration) int {
+	t := cabiriaTime.FromDuration(at)
+	for i, elem := range ranges {
+		if !t.Before(elem.Start()) && !t.After(elem.End()) {
+			return i
+		}
+	}
+	return -1
+}
+
+func withoutOverlapping(ranges []intertitle.Range, target intertitle.Range) []intertitle.Range {
+	var result []intertitle.Range
+	for _, elem := range ranges {
+		if elem.End().Before(target.Start()) || elem.Start().After(target.End()) {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
+
+func frameAt(t time.Duration, fps float64) int {
+	return int(t.Seconds() * fps)
+}
+
+func fromSeconds(seconds *float64) *time.Duration {
+	if seconds == nil {
+		return nil
+	}
+	result := time.Duration(math.Round(*seconds*1e6)) * time.Microsecond
+	return &result
+}
+
+func orDefault(col color.Color, fallback color.Color) color.Color {
+	if col == nil {
+		return fallback
+	}
+	return col
+}
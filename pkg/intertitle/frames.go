@@ -0,0 +1,21 @@
+package intertitle
+
+import (
+	"image"
+
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+)
+
+// FrameSource provides the frames of a video by index, so that the style of
+//  an intertitle may be extracted.
+type FrameSource interface {
+	Frame(index int) (image.Image, error)
+}
+
+// PNGFrames is a FrameSource of ordered paths to PNG files.
+type PNGFrames []string
+
+// Frame loads the PNG file for the frame at index.
+func (pf PNGFrames) Frame(index int) (image.Image, error) {
+	return cabiriaImage.GetPNG(pf[index])
+}
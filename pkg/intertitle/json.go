@@ -0,0 +1,89 @@
+package intertitle
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+// JSONRanges is the JSON format in which intertitle ranges are saved and
+//  loaded (see write.JSON and read.JSON).
+type JSONRanges struct {
+	Intertitles []JSONRange `json:"intertitles"`
+}
+
+// JSONRange is the JSON format of a single Range. Times are in seconds, and
+//  colors are hex (e.g. "#ffffff").
+type JSONRange struct {
+	StartFrame int     `json:"start_frame"`
+	EndFrame   int     `json:"end_frame"`
+	FPS        float64 `json:"fps"`
+	Timed      bool    `json:"timed"`
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Foreground string  `json:"foreground"`
+	Background string  `json:"background"`
+}
+
+// NewJSONRanges converts ranges to their JSON format.
+func NewJSONRanges(ranges []Range) JSONRanges {
+	result := JSONRanges{Intertitles: make([]JSONRange, len(ranges))}
+	for i, elem := range ranges {
+		result.Intertitles[i] = NewJSONRange(elem)
+	}
+	return result
+}
+
+// NewJSONRange converts ir to its JSON format.
+func NewJSONRange(ir Range) JSONRange {
+	return JSONRange{
+		StartFrame: ir.StartFrame,
+		EndFrame:   ir.EndFrame,
+		FPS:        ir.FPS,
+		Timed:      ir.Timed,
+		Start:      cabiriaTime.ToDuration(ir.Start()).Seconds(),
+		End:        cabiriaTime.ToDuration(ir.End()).Seconds(),
+		Foreground: cabiriaImage.HexColor(ir.Style.ForegroundColor),
+		Background: cabiriaImage.HexColor(ir.Style.BackgroundColor),
+	}
+}
+
+// Range converts jr back to a Range. The start and end times are only used
+//  if timed is true, otherwise the frames and FPS give the timing.
+func (jr JSONRange) Range() (Range, error) {
+	foreground, err := cabiriaImage.ParseHexColor(jr.Foreground)
+	if err != nil {
+		return Range{}, err
+	}
+	background, err := cabiriaImage.ParseHexColor(jr.Background)
+	if err != nil {
+		return Range{}, err
+	}
+	result := Range{
+		StartFrame: jr.StartFrame,
+		EndFrame:   jr.EndFrame,
+		FPS:        jr.FPS,
+		Style: Style{
+			ForegroundColor: foreground,
+			BackgroundColor: background,
+		},
+	}
+	if jr.Timed {
+		result.Timed = true
+		result.StartTime = fromSeconds(jr.Start)
+		result.EndTime = fromSeconds(jr.End)
+	}
+	if !result.Valid() {
+		return Range{}, fmt.Errorf("the frames, FPS or times are out of bounds")
+	}
+	return result, nil
+}
+
+// fromSeconds converts seconds to a duration, rounded to the microsecond to
+//  undo any error from formatting it as a decimal.
+func fromSeconds(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds*1e6)) * time.Microsecond
+}
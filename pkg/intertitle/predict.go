@@ -46,6 +46,53 @@ func (p Predictor) PredictSingle(frame image.Image) (bool, error) {
 	return mapPredictionToIntertitle(prediction), nil
 }
 
+// PredictProba gives how confident the predictor is that each frame is an
+//  intertitle, from 0.0 to 1.0.
+func (p Predictor) PredictProba(frames []image.Image) ([]float64, error) {
+	datum := mapFramesToInput(frames)
+	predictions, err := p.Predictor.PredictProba(datum)
+	if err != nil {
+		return nil, err
+	}
+	confidences := make([]float64, len(predictions))
+	for i, elem := range predictions {
+		confidences[i] = elem[0]
+	}
+	return confidences, nil
+}
+
+// PredictProbaSingle gives how confident the predictor is that a frame is an
+//  intertitle, from 0.0 to 1.0.
+func (p Predictor) PredictProbaSingle(frame image.Image) (float64, error) {
+	if frame == nil {
+		return 0.0, fmt.Errorf("cannot predict on nil images")
+	}
+	datum := intertitle.GetIntensityStats(frame).AsInput()
+	prediction, err := p.Predictor.PredictProbaSingle(datum)
+	if err != nil {
+		return 0.0, err
+	}
+	return prediction[0], nil
+}
+
+// MeanConfidence is the mean confidence over the frames of a range, given
+//  the confidence of every frame of the video. It is 0.0 if the range has no
+//  frames with a known confidence.
+func MeanConfidence(confidences []float64, ir Range) float64 {
+	sum := 0.0
+	count := 0
+	for i := ir.StartFrame; i <= ir.EndFrame && i < len(confidences); i++ {
+		if i >= 0 {
+			sum += confidences[i]
+			count++
+		}
+	}
+	if count == 0 {
+		return 0.0
+	}
+	return sum / float64(count)
+}
+
 func mapFramesToInput(frames []image.Image) []ml.Datum {
 	stats := make([]ml.Datum, len(frames))
 	for i, elem := range frames {
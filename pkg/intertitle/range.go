@@ -1,6 +1,8 @@
 package intertitle
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
@@ -9,47 +11,77 @@ import (
 )
 
 // Range defines a set of frames which encapsulate an intertitle.
-//  Range can be used as a Period.
+//  Range can be used as a Period. If Timed is set, StartTime and EndTime hold
+//  the presentation timestamps of the start and end frames, which are used
+//  instead of the FPS (e.g. for variable frame rate video).
 type Range struct {
 	StartFrame int
 	EndFrame   int
 	FPS        float64
+	Timed      bool
+	StartTime  time.Duration
+	EndTime    time.Duration
 	Style      Style
 }
 
 // Valid will return true if a range is valid, otherwise false.
 func (ir Range) Valid() bool {
-	return ir.FPS > 0.0 &&
+	if ir.Timed && (ir.StartTime < 0 || ir.StartTime > ir.EndTime) {
+		return false
+	}
+	return (ir.FPS > 0.0 || ir.Timed) &&
 		ir.StartFrame >= 0 &&
 		ir.EndFrame >= 0 &&
 		ir.StartFrame <= ir.EndFrame
 }
 
 // Start returns a time representation of the start frame of a Range,
-//  using the FPS.
+//  using the timestamp if Timed, else the FPS.
 func (ir Range) Start() time.Time {
+	if ir.Timed {
+		return cabiriaTime.FromDuration(ir.StartTime)
+	}
 	return cabiriaTime.FromFrameAndFPS(ir.StartFrame, ir.FPS)
 }
 
 // End returns a time representation of the end frame of a Range,
-//  using the FPS.
+//  using the timestamp if Timed, else the FPS.
 func (ir Range) End() time.Time {
+	if ir.Timed {
+		return cabiriaTime.FromDuration(ir.EndTime)
+	}
 	return cabiriaTime.FromFrameAndFPS(ir.EndFrame, ir.FPS)
 }
 
 // TransformToNew computes a new Range given the desired start and end times,
-//  calculating frame numbers using the FPS.
+//  calculating frame numbers using the FPS. A Timed Range keeps the exact
+//  times.
 func (ir Range) TransformToNew(start, end time.Time) period.Period {
 	return Range{
 		StartFrame: fromTimeAndFPS(start, ir.FPS),
 		EndFrame:   fromTimeAndFPS(end, ir.FPS),
 		FPS:        ir.FPS,
+		Timed:      ir.Timed,
+		StartTime:  sinceBase(start, ir.Timed),
+		EndTime:    sinceBase(end, ir.Timed),
 	}
 }
 
 // MapRanges takes an array of intertitle frames and an fps, and reduces it
-//  to an array of Ranges.
-func MapRanges(intertitles []bool, fps float64, framePaths []string) ([]Range, error) {
+//  to an array of Ranges. The style of each Range is extracted from frames.
+//  If timestamps holds the presentation timestamp of every frame, the Ranges
+//  are Timed with them; if it is nil, timing is derived from the fps.
+func MapRanges(intertitles []bool, fps float64, timestamps []time.Duration, frames FrameSource) ([]Range, error) {
+	return MapRangesContext(context.Background(), intertitles, fps, timestamps, frames)
+}
+
+// MapRangesContext is like MapRanges, but gives up if ctx is done before the
+//  style of every Range is extracted.
+func MapRangesContext(ctx context.Context, intertitles []bool, fps float64, timestamps []time.Duration, frames FrameSource) ([]Range, error) {
+	if timestamps != nil && len(timestamps) != len(intertitles) {
+		return nil, fmt.Errorf("there are %d timestamps for %d frames", len(timestamps), len(intertitles))
+	}
+
 	transitions := make([]Range, 0)
 	last := false
 	start := -1
@@ -60,25 +92,25 @@ func MapRanges(intertitles []bool, fps float64, framePaths []string) ([]Range, e
 		}
 		// End of intertitle
 		if last && !current {
-			style, err := getStyle(start, i-1, framePaths)
+			style, err := getStyle(ctx, start, i-1, frames)
 			if err != nil {
 				return nil, err
 			}
-			transitions = appendIntertitle(transitions, start, i-1, fps, style)
+			transitions = appendIntertitle(transitions, start, i-1, fps, timestamps, style)
 			start = -1
 		}
 		last = current
 	}
 	// Close off end, if applicable
-	style, err := getStyle(start, len(intertitles)-1, framePaths)
+	style, err := getStyle(ctx, start, len(intertitles)-1, frames)
 	if err != nil {
 		return nil, err
 	}
-	transitions = appendIntertitle(transitions, start, len(intertitles)-1, fps, style)
+	transitions = appendIntertitle(transitions, start, len(intertitles)-1, fps, timestamps, style)
 	return transitions, nil
 }
 
-func appendIntertitle(transitions []Range, start, end int, fps float64, style Style) []Range {
+func appendIntertitle(transitions []Range, start, end int, fps float64, timestamps []time.Duration, style Style) []Range {
 	if start < 0 {
 		return transitions
 	}
@@ -88,29 +120,36 @@ func appendIntertitle(transitions []Range, start, end int, fps float64, style St
 		FPS:        fps,
 		Style:      style,
 	}
+	if timestamps != nil {
+		new.Timed = true
+		new.StartTime = timestamps[start]
+		new.EndTime = timestamps[end]
+	}
 	return append(transitions, new)
 }
 
+func sinceBase(t time.Time, timed bool) time.Duration {
+	if !timed {
+		return 0
+	}
+	return cabiriaTime.ToDuration(t)
+}
+
 func fromTimeAndFPS(t time.Time, fps float64) int {
-	hours := time.Duration(t.Hour()) * time.Hour
-	minutes := time.Duration(t.Minute()) * time.Minute
-	seconds := time.Duration(t.Second()) * time.Second
-	nano := time.Duration(t.Nanosecond()) * time.Nanosecond
-	totalSeconds := float64(hours+minutes+seconds+nano) / float64(time.Second)
-	return int(totalSeconds * fps)
+	return int(cabiriaTime.ToDuration(t).Seconds() * fps)
 }
 
-func getStyle(start, end int, framePaths []string) (Style, error) {
+func getStyle(ctx context.Context, start, end int, frames FrameSource) (Style, error) {
 	if start < 0 {
 		return Style{}, nil
 	}
 
 	midPoint := (start + end) / 2
-	img, err := cabiriaImage.GetPNG(framePaths[midPoint])
+	img, err := frames.Frame(midPoint)
 	if err != nil {
 		return Style{}, err
 	}
-	foreground, background, err := cabiriaImage.GetForegroundAndBackground(img)
+	foreground, background, err := cabiriaImage.GetForegroundAndBackgroundContext(ctx, img)
 	if err != nil {
 		return Style{}, err
 	}
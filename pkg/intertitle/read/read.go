@@ -0,0 +1,75 @@
+package read
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/liampulles/cabiria/pkg/intertitle"
+)
+
+// Reader loads intertitle ranges from path in some format.
+type Reader func(path string) ([]intertitle.Range, error)
+
+// Formats maps the file extensions understood by Ranges to the Reader for
+//  that format.
+var Formats = map[string]Reader{
+	".json": JSON,
+}
+
+// Ranges loads intertitle ranges from path, in the format given by the
+//  extension of path (see Formats).
+func Ranges(path string) ([]intertitle.Range, error) {
+	reader, err := ForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return reader(path)
+}
+
+// ForPath finds the Reader for the format given by the extension of path.
+func ForPath(path string) (Reader, error) {
+	reader, ok := Formats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("cannot tell what format to load ranges from %s in. Use one of the extensions: %s",
+			path, strings.Join(extensions(), ", "))
+	}
+	return reader, nil
+}
+
+// JSON loads intertitle ranges from JSON saved by write.JSON. The start and
+//  end times are only used if timed is true, otherwise the frames and FPS
+//  give the timing.
+func JSON(path string) ([]intertitle.Range, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var parsed intertitle.JSONRanges
+	err = json.Unmarshal(data, &parsed)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ranges in %s: %v", path, err)
+	}
+
+	result := make([]intertitle.Range, len(parsed.Intertitles))
+	for i, elem := range parsed.Intertitles {
+		interRange, err := elem.Range()
+		if err != nil {
+			return nil, fmt.Errorf("intertitle %d in %s is invalid: %v", i+1, path, err)
+		}
+		result[i] = interRange
+	}
+	return result, nil
+}
+
+func extensions() []string {
+	var result []string
+	for ext := range Formats {
+		result = append(result, ext)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,81 @@
+package intertitle
+
+import (
+	"sort"
+	"time"
+)
+
+// SnapRanges moves the start of each range to the closest frame in starts,
+//  and the end to the closest frame in ends (e.g. cuts and fades), if it is
+//  within tolerance frames. starts and ends must be sorted. Ranges are kept
+//  in order and from overlapping. Timed ranges are retimed with timestamps,
+//  which holds the presentation timestamp of every frame; if it is nil, Timed
+//  ranges are left as they are.
+func SnapRanges(ranges []Range, starts, ends []int, tolerance int, timestamps []time.Duration) []Range {
+	result := make([]Range, len(ranges))
+	copy(result, ranges)
+	if tolerance <= 0 {
+		return result
+	}
+	for i, elem := range result {
+		if elem.Timed && timestamps == nil {
+			continue
+		}
+		earliest := 0
+		if i > 0 {
+			earliest = result[i-1].EndFrame + 1
+		}
+		latest := -1
+		if i+1 < len(result) {
+			latest = result[i+1].StartFrame - 1
+		}
+
+		start := closest(starts, elem.StartFrame, tolerance, earliest, elem.EndFrame)
+		end := elem.EndFrame
+		if latest < 0 || end <= latest {
+			end = closest(ends, elem.EndFrame, tolerance, start, latest)
+		}
+		result[i] = retimed(elem, start, end, timestamps)
+	}
+	return result
+}
+
+// closest finds the frame in frames which is closest to target, within
+//  tolerance and between min and max (or unbounded above if max is
+//  negative). If there is none, target is returned. Ties go to the earlier
+//  frame.
+func closest(frames []int, target, tolerance, min, max int) int {
+	result := target
+	bestDistance := tolerance + 1
+	from := sort.SearchInts(frames, target-tolerance)
+	for _, elem := range frames[from:] {
+		if elem > target+tolerance {
+			break
+		}
+		if elem < min || (max >= 0 && elem > max) {
+			continue
+		}
+		distance := elem - target
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < bestDistance {
+			result = elem
+			bestDistance = distance
+		}
+	}
+	return result
+}
+
+func retimed(ir Range, start, end int, timestamps []time.Duration) Range {
+	if ir.Timed {
+		if start >= len(timestamps) || end >= len(timestamps) {
+			return ir
+		}
+		ir.StartTime = timestamps[start]
+		ir.EndTime = timestamps[end]
+	}
+	ir.StartFrame = start
+	ir.EndFrame = end
+	return ir
+}
@@ -0,0 +1,180 @@
+package write
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+// Writer saves intertitle ranges to path in some format.
+type Writer func(ranges []intertitle.Range, path string) error
+
+// Formats maps the file extensions understood by Ranges to the Writer for
+//  that format.
+var Formats = map[string]Writer{
+	".json":       JSON,
+	".csv":        CSV,
+	".ffmetadata": FFMetadata,
+	".xml":        MatroskaChapters,
+}
+
+// Ranges saves intertitle ranges to path, in the format given by the
+//  extension of path (see Formats).
+func Ranges(ranges []intertitle.Range, path string) error {
+	writer, err := ForPath(path)
+	if err != nil {
+		return err
+	}
+	return writer(ranges, path)
+}
+
+// ForPath finds the Writer for the format given by the extension of path.
+func ForPath(path string) (Writer, error) {
+	writer, ok := Formats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("cannot tell what format to save ranges to %s in. Use one of the extensions: %s",
+			path, strings.Join(extensions(), ", "))
+	}
+	return writer, nil
+}
+
+// JSON saves intertitle ranges to path as JSON, e.g.:
+//
+//    {
+//      "intertitles": [
+//        {
+//          "start_frame": 100,
+//          "end_frame": 159,
+//          "fps": 25,
+//          "timed": false,
+//          "start": 4,
+//          "end": 6.36,
+//          "foreground": "#ffffff",
+//          "background": "#191919"
+//        }
+//      ]
+//    }
+//
+//  Times are in seconds. If timed is true, the start and end are frame
+//  timestamps rather than being derived from the frames and FPS.
+func JSON(ranges []intertitle.Range, path string) error {
+	data, err := json.MarshalIndent(intertitle.NewJSONRanges(ranges), "", "  ")
+	if err != nil {
+		return err
+	}
+	return file.SaveTextToFile(path, string(data)+"\n")
+}
+
+// CSV saves intertitle ranges to path as CSV, with a header. Times are in
+//  seconds, and the end is when the end frame stops showing (see endOf).
+func CSV(ranges []intertitle.Range, path string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"index", "start_frame", "end_frame", "start", "end", "foreground", "background"})
+	for i, elem := range ranges {
+		w.Write([]string{
+			strconv.Itoa(i + 1),
+			strconv.Itoa(elem.StartFrame),
+			strconv.Itoa(elem.EndFrame),
+			formatSeconds(elem.Start()),
+			formatSeconds(endOf(elem)),
+			cabiriaImage.HexColor(elem.Style.ForegroundColor),
+			cabiriaImage.HexColor(elem.Style.BackgroundColor),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return file.SaveTextToFile(path, buf.String())
+}
+
+// FFMetadata saves intertitle ranges to path as chapters in FFmpeg's metadata
+//  format, ending when the end frame stops showing (see endOf). They can be
+//  added to a video with e.g.
+//  "ffmpeg -i in.mkv -i chapters.ffmetadata -map_chapters 1 -codec copy out.mkv"
+func FFMetadata(ranges []intertitle.Range, path string) error {
+	text := ";FFMETADATA1\n"
+	for i, elem := range ranges {
+		text += fmt.Sprintf("\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			milliseconds(elem.Start()),
+			milliseconds(endOf(elem)),
+			chapterTitle(i))
+	}
+	return file.SaveTextToFile(path, text)
+}
+
+// MatroskaChapters saves intertitle ranges to path as Matroska chapters XML,
+//  ending when the end frame stops showing (see endOf). They can be added to
+//  a video with e.g.
+//  "mkvmerge -o out.mkv --chapters chapters.xml in.mkv"
+func MatroskaChapters(ranges []intertitle.Range, path string) error {
+	text := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+		"<!DOCTYPE Chapters SYSTEM \"matroskachapters.dtd\">\n" +
+		"<Chapters>\n" +
+		"  <EditionEntry>\n"
+	for i, elem := range ranges {
+		text += "    <ChapterAtom>\n" +
+			fmt.Sprintf("      <ChapterTimeStart>%s</ChapterTimeStart>\n", matroskaTimestamp(elem.Start())) +
+			fmt.Sprintf("      <ChapterTimeEnd>%s</ChapterTimeEnd>\n", matroskaTimestamp(endOf(elem))) +
+			"      <ChapterDisplay>\n" +
+			fmt.Sprintf("        <ChapterString>%s</ChapterString>\n", chapterTitle(i)) +
+			"        <ChapterLanguage>eng</ChapterLanguage>\n" +
+			"      </ChapterDisplay>\n" +
+			"    </ChapterAtom>\n"
+	}
+	text += "  </EditionEntry>\n" +
+		"</Chapters>\n"
+	return file.SaveTextToFile(path, text)
+}
+
+// endOf returns when ir stops showing, rather than when its end frame starts
+//  (as ir.End() does): its end time if it is Timed, else one frame after its
+//  end frame.
+func endOf(ir intertitle.Range) time.Time {
+	if ir.Timed {
+		return ir.End()
+	}
+	return cabiriaTime.FromFrameAndFPS(ir.EndFrame+1, ir.FPS)
+}
+
+func chapterTitle(index int) string {
+	return fmt.Sprintf("Intertitle %d", index+1)
+}
+
+func seconds(t time.Time) float64 {
+	return cabiriaTime.ToDuration(t).Seconds()
+}
+
+func formatSeconds(t time.Time) string {
+	return strconv.FormatFloat(seconds(t), 'f', 3, 64)
+}
+
+func milliseconds(t time.Time) int64 {
+	return int64(cabiriaTime.ToDuration(t).Round(time.Millisecond) / time.Millisecond)
+}
+
+// matroskaTimestamp formats t as e.g. "01:02:03.450000000"
+func matroskaTimestamp(t time.Time) string {
+	return cabiriaTime.ToTimecode(t, 9)
+}
+
+func extensions() []string {
+	var result []string
+	for ext := range Formats {
+		result = append(result, ext)
+	}
+	sort.Strings(result)
+	return result
+}
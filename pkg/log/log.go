@@ -0,0 +1,150 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message.
+type Level int
+
+// Levels, from least to most severe.
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// String gives the name of the level, e.g. "info"
+func (l Level) String() string {
+	if l < Debug || l > Error {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel finds the level with the given name, e.g. "warn".
+func ParseLevel(name string) (Level, error) {
+	for i, elem := range levelNames {
+		if strings.EqualFold(name, elem) {
+			return Level(i), nil
+		}
+	}
+	return Debug, fmt.Errorf("unknown log level %q. Valid levels are: %s", name, strings.Join(levelNames, ", "))
+}
+
+// Logger writes structured log messages in logfmt, e.g.:
+//
+//    time=2020-05-01T10:00:00Z level=info msg="Saved ASS" path=film.ass
+//
+//  Messages less severe than the level of the logger are dropped. It is safe
+//  for concurrent use.
+type Logger struct {
+	out     io.Writer
+	level   Level
+	now     func() time.Time
+	keyvals []interface{}
+	mutex   *sync.Mutex
+}
+
+// New creates a Logger which writes messages of at least level to out.
+func New(out io.Writer, level Level) *Logger {
+	return NewWithClock(out, level, time.Now)
+}
+
+// NewWithClock creates a Logger which writes messages of at least level to
+//  out, and uses now to timestamp them.
+func NewWithClock(out io.Writer, level Level, now func() time.Time) *Logger {
+	return &Logger{
+		out:   out,
+		level: level,
+		now:   now,
+		mutex: &sync.Mutex{},
+	}
+}
+
+// With creates a Logger which attaches keyvals to each message, before any
+//  given with the message, e.g. to tell apart messages about several films.
+//  Otherwise it is the same as l, and writes to the same place.
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	result := *l
+	result.keyvals = append(l.keyvals[:len(l.keyvals):len(l.keyvals)], keyvals...)
+	return &result
+}
+
+// Debug logs a message with detail useful for diagnosing problems. keyvals
+//  are alternating keys and values to attach to the message.
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(Debug, msg, keyvals)
+}
+
+// Info logs a message about normal operation. keyvals are alternating keys
+//  and values to attach to the message.
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log(Info, msg, keyvals)
+}
+
+// Warn logs a message about something unexpected, which can be recovered
+//  from. keyvals are alternating keys and values to attach to the message.
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(Warn, msg, keyvals)
+}
+
+// Error logs a message about a failure. keyvals are alternating keys and
+//  values to attach to the message.
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log(Error, msg, keyvals)
+}
+
+func (l *Logger) log(level Level, msg string, keyvals []interface{}) {
+	if level < l.level {
+		return
+	}
+	keyvals = append(l.keyvals[:len(l.keyvals):len(l.keyvals)], keyvals...)
+	var line strings.Builder
+	line.WriteString("time=")
+	line.WriteString(l.now().UTC().Format(time.RFC3339))
+	line.WriteString(" level=")
+	line.WriteString(level.String())
+	line.WriteString(" msg=")
+	line.WriteString(formatValue(msg))
+	for i := 0; i < len(keyvals); i += 2 {
+		line.WriteString(" ")
+		line.WriteString(fmt.Sprint(keyvals[i]))
+		line.WriteString("=")
+		if i+1 < len(keyvals) {
+			line.WriteString(formatValue(keyvals[i+1]))
+		} else {
+			line.WriteString("(MISSING)")
+		}
+	}
+	line.WriteString("\n")
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	io.WriteString(l.out, line.String())
+}
+
+// formatValue formats a value, quoting it if it would otherwise be ambiguous.
+func formatValue(value interface{}) string {
+	var str string
+	switch v := value.(type) {
+	case error:
+		str = v.Error()
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		str = fmt.Sprint(v)
+	}
+	if str == "" || strings.ContainsAny(str, " =\"\t\r\n") {
+		return strconv.Quote(str)
+	}
+	return str
+}
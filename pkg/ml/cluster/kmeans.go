@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"math"
 
@@ -29,6 +30,12 @@ func NewKMeansClassifier(k int, maxIterations int) *KMeansClassifier {
 //  the number of values that associate with each centroid and the number of iterations
 //  is returned
 func (kmc *KMeansClassifier) Fit(input []ml.Datum) ([]int, int, error) {
+	return kmc.FitContext(context.Background(), input)
+}
+
+// FitContext is like Fit, but gives up (returning ctx's error) if ctx is done
+//  before the centroids are found.
+func (kmc *KMeansClassifier) FitContext(ctx context.Context, input []ml.Datum) ([]int, int, error) {
 	if len(input) < kmc.k {
 		return nil, -1, fmt.Errorf("Cannot assign %d clusters to input of length %d", kmc.k, len(input))
 	}
@@ -45,6 +52,9 @@ func (kmc *KMeansClassifier) Fit(input []ml.Datum) ([]int, int, error) {
 	// -> This is in-case the input has empty datums, we won't enter the loop so we'll return this
 	centroidCounts[0] = len(input)
 	for noIterations < kmc.maxIterations && someChangeInCentroids(currentCentroids, lastCentroids) {
+		if err := ctx.Err(); err != nil {
+			return nil, -1, err
+		}
 
 		// We'll keep a running total of each element assigned to a centroid, so we can get
 		// the mean later.
@@ -11,10 +11,14 @@ type Sample struct {
 
 // Predictor defines the methods necessary to Train, Predict, and Save a
 //  ML model. The design is influence by scikit-learn (https://scikit-learn.org/stable/about.html).
+//  PredictProba gives how confident the model is in each element of the
+//  output, from 0.0 to 1.0, rather than a single best guess.
 type Predictor interface {
 	Fit(samples []Sample) error
 	Predict(input []Datum) ([]Datum, error)
 	PredictSingle(input Datum) (Datum, error)
+	PredictProba(input []Datum) ([]Datum, error)
+	PredictProbaSingle(input Datum) (Datum, error)
 	Save(path string) error
 }
 
@@ -90,6 +90,31 @@ func (kc *KNNClassifier) PredictSingle(input Datum) (Datum, error) {
 	return closeSample.Output, nil
 }
 
+// PredictProba finds the K "closest" known Samples for each given Datum, and
+//  returns the vote fraction of their outputs (see PredictProbaSingle).
+func (kc *KNNClassifier) PredictProba(input []Datum) ([]Datum, error) {
+	output := make([]Datum, len(input))
+	for i, elem := range input {
+		target, err := kc.PredictProbaSingle(elem)
+		if err != nil {
+			return nil, err
+		}
+		output[i] = target
+	}
+	return output, nil
+}
+
+// PredictProbaSingle finds the K "closest" known Samples to the given Datum,
+//  and returns the mean of their outputs. For outputs which are 0.0 or 1.0
+//  (or one-hot), this is the fraction of the Samples voting for each.
+func (kc *KNNClassifier) PredictProbaSingle(input Datum) (Datum, error) {
+	closeSamples, err := findClosestK(kc.Points, input, kc.K)
+	if err != nil {
+		return nil, err
+	}
+	return mean(closeSamples)
+}
+
 // Save saves a KNNClassifier to disk.
 func (kc *KNNClassifier) Save(path string) error {
 	// Register type
@@ -124,17 +149,24 @@ func (adp argDistPairs) Swap(i, j int)      { adp[i], adp[j] = adp[j], adp[i] }
 func (adp argDistPairs) Less(i, j int) bool { return adp[i].Dist < adp[j].Dist }
 
 func findClosest(samples []Sample, closestTo Datum, k uint) (Sample, error) {
+	closestSamples, err := findClosestK(samples, closestTo, k)
+	if err != nil {
+		return Sample{}, err
+	}
+	return mode(closestSamples)
+}
+
+func findClosestK(samples []Sample, closestTo Datum, k uint) ([]Sample, error) {
 	pairs := make([]argDistPair, len(samples))
 	for i, sample := range samples {
 		dist, err := cabiriaMath.SquareDistance(closestTo, sample.Input)
 		if err != nil {
-			return Sample{}, err
+			return nil, err
 		}
 		pairs[i] = argDistPair{i, dist}
 	}
 	closestArgs := minKDistArg(pairs, k)
-	closestSamples := selectByArgs(samples, closestArgs)
-	return mode(closestSamples)
+	return selectByArgs(samples, closestArgs), nil
 }
 
 func minKDistArg(pairs argDistPairs, k uint) []int {
@@ -173,6 +205,23 @@ func mode(samples []Sample) (Sample, error) {
 	return samples[maxArg(pairMatches)], nil
 }
 
+func mean(samples []Sample) (Datum, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("cannot find the mean output of no samples")
+	}
+	result := make(Datum, len(samples[0].Output))
+	for _, elem := range samples {
+		if len(elem.Output) != len(result) {
+			return nil, fmt.Errorf("cannot find the mean of outputs with different lengths. Lengths: %d, %d",
+				len(result), len(elem.Output))
+		}
+		for i, value := range elem.Output {
+			result[i] += value / float64(len(samples))
+		}
+	}
+	return result, nil
+}
+
 func maxArg(input []uint) int {
 	largest := uint(0)
 	largestI := -1
@@ -48,6 +48,17 @@ func (p *DummyPredictor) PredictSingle(input ml.Datum) (ml.Datum, error) {
 	return elem, nil
 }
 
+// PredictProba returns mock data for input, as if the model is certain of it.
+func (p *DummyPredictor) PredictProba(input []ml.Datum) ([]ml.Datum, error) {
+	return p.Predict(input)
+}
+
+// PredictProbaSingle returns mock data for input, as if the model is certain
+//  of it.
+func (p *DummyPredictor) PredictProbaSingle(input ml.Datum) (ml.Datum, error) {
+	return p.PredictSingle(input)
+}
+
 // Save doe snot work for DummyPredictors, do not use it.
 func (p *DummyPredictor) Save(path string) error {
 	return fmt.Errorf("dummyPredictor cannot be saved")
@@ -0,0 +1,256 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reporter is notified of the progress of the stages of a long running
+//  process. Several stages may be in progress at once. Implementations must
+//  be safe for concurrent use.
+type Reporter interface {
+	// Start begins a stage with the given total amount of work, or 0 if the
+	//  total is unknown.
+	Start(stage string, total int)
+	// Update sets how much of a stage's work is done.
+	Update(stage string, done int)
+	// Finish ends a stage.
+	Finish(stage string)
+}
+
+// Quiet is a Reporter which reports nothing, e.g. for use in scripts.
+type Quiet struct{}
+
+// Start does nothing.
+func (q Quiet) Start(stage string, total int) {}
+
+// Update does nothing.
+func (q Quiet) Update(stage string, done int) {}
+
+// Finish does nothing.
+func (q Quiet) Finish(stage string) {}
+
+// redrawInterval is the minimum time between redraws of a Terminal.
+const redrawInterval = 100 * time.Millisecond
+
+// Terminal is a Reporter which keeps a single line up to date with the
+//  percentage, throughput and ETA of each stage in progress. Each stage gets
+//  a line of its own once finished.
+type Terminal struct {
+	out        io.Writer
+	now        func() time.Time
+	mutex      sync.Mutex
+	stages     []*stage
+	lastRedraw time.Time
+}
+
+type stage struct {
+	name    string
+	total   int
+	done    int
+	started time.Time
+}
+
+// NewTerminal creates a Terminal which writes to out.
+func NewTerminal(out io.Writer) *Terminal {
+	return NewTerminalWithClock(out, time.Now)
+}
+
+// NewTerminalWithClock creates a Terminal which writes to out, and uses now
+//  to tell the time.
+func NewTerminalWithClock(out io.Writer, now func() time.Time) *Terminal {
+	return &Terminal{
+		out: out,
+		now: now,
+	}
+}
+
+// Start begins a stage and shows it.
+func (t *Terminal) Start(name string, total int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.stages = append(t.stages, &stage{
+		name:    name,
+		total:   total,
+		started: t.now(),
+	})
+	t.redraw()
+}
+
+// Update sets how much of a stage's work is done. To avoid flicker, the line
+//  is only redrawn every so often.
+func (t *Terminal) Update(name string, done int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	s := t.find(name)
+	if s == nil {
+		return
+	}
+	s.done = done
+	if t.now().Sub(t.lastRedraw) >= redrawInterval {
+		t.redraw()
+	}
+}
+
+// Finish ends a stage, and prints how long it took on a line of its own.
+func (t *Terminal) Finish(name string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i, s := range t.stages {
+		if s.name == name {
+			t.stages = append(t.stages[:i], t.stages[i+1:]...)
+			fmt.Fprintf(t.out, "\r\033[K%s: DONE in %s\n", name, formatDuration(t.now().Sub(s.started)))
+			t.redraw()
+			return
+		}
+	}
+}
+
+// Writer returns a writer for other messages (e.g. log messages) shown on
+//  the same terminal. The progress line is cleared before each write and
+//  redrawn after it, so that the two do not garble each other. Each write
+//  should be whole lines.
+func (t *Terminal) Writer() io.Writer {
+	return terminalWriter{terminal: t}
+}
+
+type terminalWriter struct {
+	terminal *Terminal
+}
+
+func (tw terminalWriter) Write(p []byte) (int, error) {
+	t := tw.terminal
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.stages) > 0 {
+		io.WriteString(t.out, "\r\033[K")
+	}
+	n, err := t.out.Write(p)
+	t.redraw()
+	return n, err
+}
+
+func (t *Terminal) find(name string) *stage {
+	for _, s := range t.stages {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (t *Terminal) redraw() {
+	now := t.now()
+	t.lastRedraw = now
+	if len(t.stages) == 0 {
+		return
+	}
+	descriptions := make([]string, len(t.stages))
+	for i, s := range t.stages {
+		descriptions[i] = s.describe(now)
+	}
+	fmt.Fprintf(t.out, "\r\033[K%s", strings.Join(descriptions, " | "))
+}
+
+// describe gives the progress of the stage, e.g.
+//  "Predicting: 45% (1234/2740, 310.2/s, ETA 0:05)"
+func (s *stage) describe(now time.Time) string {
+	if s.done <= 0 {
+		return s.name + "..."
+	}
+	elapsed := now.Sub(s.started)
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(s.done) / elapsed.Seconds()
+	}
+	if s.total <= 0 {
+		return fmt.Sprintf("%s: %d (%.1f/s)", s.name, s.done, rate)
+	}
+	percentage := 100 * s.done / s.total
+	eta := "?"
+	if rate > 0 && s.done <= s.total {
+		eta = formatDuration(time.Duration(float64(s.total-s.done) / rate * float64(time.Second)))
+	}
+	return fmt.Sprintf("%s: %d%% (%d/%d, %.1f/s, ETA %s)", s.name, percentage, s.done, s.total, rate, eta)
+}
+
+// formatDuration formats d as e.g. "1:02:03" or "2:03", rounded to the
+//  second.
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// StageTiming is how long a stage took.
+type StageTiming struct {
+	Stage    string
+	Duration time.Duration
+}
+
+// Timed is a Reporter which records how long each stage took, and passes
+//  everything on to another Reporter.
+type Timed struct {
+	next    Reporter
+	now     func() time.Time
+	mutex   sync.Mutex
+	started map[string]time.Time
+	timings []StageTiming
+}
+
+// NewTimed creates a Timed which passes everything on to next.
+func NewTimed(next Reporter) *Timed {
+	return NewTimedWithClock(next, time.Now)
+}
+
+// NewTimedWithClock creates a Timed which passes everything on to next, and
+//  uses now to tell the time.
+func NewTimedWithClock(next Reporter, now func() time.Time) *Timed {
+	return &Timed{
+		next:    next,
+		now:     now,
+		started: make(map[string]time.Time),
+	}
+}
+
+// Start notes when a stage started.
+func (t *Timed) Start(name string, total int) {
+	t.mutex.Lock()
+	t.started[name] = t.now()
+	t.mutex.Unlock()
+	t.next.Start(name, total)
+}
+
+// Update passes the update on.
+func (t *Timed) Update(name string, done int) {
+	t.next.Update(name, done)
+}
+
+// Finish notes how long a stage took.
+func (t *Timed) Finish(name string) {
+	t.mutex.Lock()
+	if started, ok := t.started[name]; ok {
+		delete(t.started, name)
+		t.timings = append(t.timings, StageTiming{
+			Stage:    name,
+			Duration: t.now().Sub(started),
+		})
+	}
+	t.mutex.Unlock()
+	t.next.Finish(name)
+}
+
+// Timings gives how long each finished stage took, in the order they
+//  finished.
+func (t *Timed) Timings() []StageTiming {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	result := make([]StageTiming, len(t.timings))
+	copy(result, t.timings)
+	return result
+}
@@ -0,0 +1,163 @@
+package sequence
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/liampulles/cabiria/pkg/array"
+)
+
+// Smoother decides which frames of a video are intertitles, given how
+//  confident a predictor is (from 0 to 1) that each frame is one. Noise in
+//  the confidences is smoothed over, so that intertitles do not flicker.
+type Smoother interface {
+	Smooth(confidences []float64) []bool
+}
+
+// Options configures the Smoothers made by NewSmoother. Lengths are in
+//  frames.
+type Options struct {
+	// Low and High are the confidence thresholds for hysteresis.
+	Low  float64
+	High float64
+	// Closing is the longest gap in an intertitle which is filled in.
+	Closing uint
+	// Opening is the shortest intertitle which is kept.
+	Opening uint
+}
+
+// Names of the Smoothers made by NewSmoother
+const (
+	MorphologicalName = "morphological"
+	HMMName           = "hmm"
+)
+
+// NewSmoother returns the Smoother with the given name, which may be
+//  "morphological" or "hmm".
+func NewSmoother(name string, options Options) (Smoother, error) {
+	switch name {
+	case MorphologicalName:
+		return Morphological{
+			Low:     options.Low,
+			High:    options.High,
+			Closing: options.Closing,
+			Opening: options.Opening,
+		}, nil
+	case HMMName:
+		return NewHMM(maxUint(options.Closing, options.Opening)), nil
+	}
+	return nil, fmt.Errorf("unknown smoothing: %s. Valid smoothings are: %s, %s", name, MorphologicalName, HMMName)
+}
+
+// Morphological thresholds the confidences with hysteresis (see
+//  array.HysteresisThreshold), then closes gaps shorter than Closing and
+//  opens intertitles shorter than Opening, in that order.
+type Morphological struct {
+	Low     float64
+	High    float64
+	Closing uint
+	Opening uint
+}
+
+// Smooth decides which frames are intertitles.
+func (m Morphological) Smooth(confidences []float64) []bool {
+	result := array.HysteresisThreshold(confidences, m.Low, m.High)
+	array.CloseBoolArray(result, m.Closing)
+	array.OpenBoolArray(result, m.Opening)
+	return result
+}
+
+// hmmFloor bounds how certain the HMM takes any single frame to be, so that
+//  enough confidently mispredicted frames in a row can still be overruled.
+const hmmFloor = 0.05
+
+// HMM treats the frames as a two-state hidden Markov model (intertitle or
+//  not), where the confidences are the observations, and finds the most
+//  likely sequence of states with the Viterbi algorithm. Unlike
+//  Morphological, gaps and intertitles are weighed together in one pass, so
+//  the order of smoothing does not matter, and less confident frames are
+//  easier to overrule.
+type HMM struct {
+	// SwitchCost is the negative log likelihood of changing state between
+	//  frames, relative to staying in the same state.
+	SwitchCost float64
+}
+
+// NewHMM creates an HMM which smooths over runs (of intertitle or not) of
+//  confidently predicted frames shorter than minRun. Runs at the start or end
+//  only need one switch to keep, so are kept if at least half as long.
+func NewHMM(minRun uint) HMM {
+	if minRun == 0 {
+		return HMM{}
+	}
+	// Keeping a run costs two switches, and smoothing over it costs
+	//  overruling each of its frames. The half frame avoids ties.
+	overrule := emissionCost(hmmFloor, true) - emissionCost(hmmFloor, false)
+	return HMM{
+		SwitchCost: (float64(minRun) - 0.5) * overrule / 2.0,
+	}
+}
+
+// Smooth decides which frames are intertitles.
+func (h HMM) Smooth(confidences []float64) []bool {
+	if len(confidences) == 0 {
+		return []bool{}
+	}
+
+	// cost[s] is the least cost of any path ending in state s, where state 1
+	//  is an intertitle. from[t][s] is the state before s in that path.
+	var cost [2]float64
+	from := make([][2]int, len(confidences))
+	for s := 0; s < 2; s++ {
+		cost[s] = emissionCost(confidences[0], s == 1)
+	}
+	for t := 1; t < len(confidences); t++ {
+		var next [2]float64
+		for s := 0; s < 2; s++ {
+			stay := cost[s]
+			switched := cost[1-s] + h.SwitchCost
+			// Prefer staying when tied
+			if stay <= switched {
+				next[s] = stay
+				from[t][s] = s
+			} else {
+				next[s] = switched
+				from[t][s] = 1 - s
+			}
+			next[s] += emissionCost(confidences[t], s == 1)
+		}
+		cost = next
+	}
+
+	// Trace back the best path, preferring no intertitle when tied
+	result := make([]bool, len(confidences))
+	state := 0
+	if cost[1] < cost[0] {
+		state = 1
+	}
+	for t := len(confidences) - 1; t >= 0; t-- {
+		result[t] = state == 1
+		state = from[t][state]
+	}
+	return result
+}
+
+// emissionCost is the negative log likelihood of the confidence, if the
+//  frame is (or is not) an intertitle.
+func emissionCost(confidence float64, intertitle bool) float64 {
+	if math.IsNaN(confidence) {
+		confidence = 0.5
+	}
+	p := math.Min(math.Max(confidence, hmmFloor), 1.0-hmmFloor)
+	if !intertitle {
+		p = 1.0 - p
+	}
+	return -math.Log(p)
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -9,11 +9,29 @@ import (
 	"github.com/liampulles/cabiria/pkg/intertitle"
 )
 
+// Assignment records which intertitle a subtitle was aligned to.
+type Assignment struct {
+	// Subtitle is as it was before alignment.
+	Subtitle Subtitle
+	// Intertitle is the range the subtitle was aligned to, if Matched.
+	Intertitle intertitle.Range
+	// Matched is false if the subtitle did not overlap any intertitle.
+	Matched bool
+}
+
 // AlignSubtitles tries to align the given subtitles to the detected intertitles
 //  such that when the subtitles are played back, they align exactly with each
 //  intertitle segment in the film - barring some edge cases arising due to
 //  imperfect data.
 func AlignSubtitles(subs []Subtitle, interRanges []intertitle.Range) []Subtitle {
+	aligned, _ := AlignSubtitlesWithAssignments(subs, interRanges)
+	return aligned
+}
+
+// AlignSubtitlesWithAssignments is like AlignSubtitles, but also returns which
+//  intertitle each subtitle was assigned to, in order of the subtitles'
+//  original start times.
+func AlignSubtitlesWithAssignments(subs []Subtitle, interRanges []intertitle.Range) ([]Subtitle, []Assignment) {
 	joined := rangedSortedSet(subs, interRanges)
 	overlaps := overlappingSets(joined)
 	return alignSubtitlesFromOverlappingSets(overlaps)
@@ -42,16 +60,18 @@ func overlappingSets(sortedRanges []period.Period) [][]period.Period {
 	return overlappingSets
 }
 
-func alignSubtitlesFromOverlappingSets(sets [][]period.Period) []Subtitle {
+func alignSubtitlesFromOverlappingSets(sets [][]period.Period) ([]Subtitle, []Assignment) {
 	var subs []Subtitle
+	var assignments []Assignment
 	for _, elem := range sets {
-		elemSubs := alignSubtitlesFromOverlappingSet(elem)
+		elemSubs, elemAssignments := alignSubtitlesFromOverlappingSet(elem)
 		subs = append(subs, elemSubs...)
+		assignments = append(assignments, elemAssignments...)
 	}
-	return subs
+	return subs, assignments
 }
 
-func alignSubtitlesFromOverlappingSet(set []period.Period) []Subtitle {
+func alignSubtitlesFromOverlappingSet(set []period.Period) ([]Subtitle, []Assignment) {
 	// separate into intertitleRange and subtitle sets
 	var interRangePeriods period.Periods
 	var subtitlePeriods period.Periods
@@ -66,16 +86,22 @@ func alignSubtitlesFromOverlappingSet(set []period.Period) []Subtitle {
 	// -> If no intertitles, or no subs -> Fix and return subs. //TODO: Maybe nil?
 	// TODO: Attach default style.
 	if len(interRangePeriods) == 0 || len(subtitlePeriods) == 0 {
-		return applyDefaultStyle(periodsAsSubs(period.FixOverlaps(subtitlePeriods)))
+		var unmatched []Assignment
+		for _, sub := range subtitlePeriods {
+			unmatched = append(unmatched, Assignment{Subtitle: sub.(Subtitle)})
+		}
+		return applyDefaultStyle(periodsAsSubs(period.FixOverlaps(subtitlePeriods))), unmatched
 	}
 
 	// Scale the subtitle set to match the intertitleRange set bounds
+	originals := subtitlePeriods
 	subtitlePeriods = subtitlePeriods.TransformToNew(interRangePeriods.Start(), interRangePeriods.End()).(period.Periods)
 
 	// For each sub, determine which intertitle they MOST overlap with,
 	//  and add them to the "bucket" for that intertitle.
 	overlapBuckets := make([][]period.Period, len(interRangePeriods))
-	for _, sub := range subtitlePeriods {
+	assignments := make([]Assignment, len(subtitlePeriods))
+	for j, sub := range subtitlePeriods {
 		maxOverlap := time.Duration(-1)
 		var maxIdx int
 		for i, interRange := range interRangePeriods {
@@ -85,6 +111,11 @@ func alignSubtitlesFromOverlappingSet(set []period.Period) []Subtitle {
 			}
 		}
 		overlapBuckets[maxIdx] = append(overlapBuckets[maxIdx], sub)
+		assignments[j] = Assignment{
+			Subtitle:   originals[j].(Subtitle),
+			Intertitle: interRangePeriods[maxIdx].(intertitle.Range),
+			Matched:    true,
+		}
 	}
 
 	// For each intertitle bucket,
@@ -108,7 +139,7 @@ func alignSubtitlesFromOverlappingSet(set []period.Period) []Subtitle {
 	result = period.FixOverlaps(result)
 
 	// return final set
-	return periodsAsSubs(result)
+	return periodsAsSubs(result), assignments
 }
 
 func copyStyle(subs []period.Period, interRange period.Period) []period.Period {
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
 )
 
 // Subtitle defines a single subtitle in a set of subtitles, that
@@ -12,5 +13,8 @@ type Subtitle struct {
 	StartTime time.Time
 	EndTime   time.Time
 	Text      string
-	Style     intertitle.Style
+	// Spans is Text split by its inline formatting (e.g. italics), or nil if
+	//  it has none.
+	Spans []style.Span
+	Style intertitle.Style
 }
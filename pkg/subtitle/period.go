@@ -28,6 +28,7 @@ func (s Subtitle) TransformToNew(start, end time.Time) period.Period {
 		StartTime: start,
 		EndTime:   end,
 		Text:      s.Text,
+		Spans:     s.Spans,
 		Style:     s.Style,
 	}
 }
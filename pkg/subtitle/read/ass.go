@@ -0,0 +1,129 @@
+package read
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	"github.com/liampulles/cabiria/pkg/subtitle"
+)
+
+// defaultASSFormat is the order of the fields of a Dialogue line in an ASS
+//  file which does not give a Format line.
+var defaultASSFormat = []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+
+// ASS loads the Dialogue lines of the [Events] section of the ASS or SSA file
+//  pointed to by path. Override blocks (e.g. {\i1}) are removed from the
+//  text, \N line breaks are kept, and \{ and \} are literal braces.
+func ASS(path string, encoding string) ([]subtitle.Subtitle, []Warning, error) {
+	lines, err := file.ReadLinesFromEncodedTextFile(path, encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var subs []subtitle.Subtitle
+	inEvents := false
+	format := defaultASSFormat
+	for i, line := range lines {
+		line = strings.TrimSpace(cleanLine(line, i))
+		if strings.HasPrefix(line, "[") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !inEvents {
+			continue
+		}
+		key, value := assField(line)
+		switch key {
+		case "format":
+			format = strings.Split(strings.ToLower(value), ",")
+			for j := range format {
+				format[j] = strings.TrimSpace(format[j])
+			}
+			if format[len(format)-1] != "text" {
+				return nil, nil, fmt.Errorf("%s:%d: the last field of the event format must be Text", path, i+1)
+			}
+		case "dialogue":
+			sub, err := assDialogue(value, format)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s:%d: %v", path, i+1, err)
+			}
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil, nil
+}
+
+// assField splits a line such as "Dialogue: 0,..." into its lowercase key
+//  and value.
+func assField(line string) (string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", ""
+	}
+	return strings.ToLower(strings.TrimSpace(line[:colon])), strings.TrimSpace(line[colon+1:])
+}
+
+func assDialogue(value string, format []string) (subtitle.Subtitle, error) {
+	// The text may itself contain commas, so is everything after the last
+	//  separator.
+	fields := strings.SplitN(value, ",", len(format))
+	if len(fields) != len(format) {
+		return subtitle.Subtitle{}, fmt.Errorf("expected %d fields in the dialogue, but found %d", len(format), len(fields))
+	}
+	var sub subtitle.Subtitle
+	for i, elem := range format {
+		var err error
+		switch elem {
+		case "start":
+			sub.StartTime, err = parseClock(strings.TrimSpace(fields[i]))
+		case "end":
+			sub.EndTime, err = parseClock(strings.TrimSpace(fields[i]))
+		case "text":
+			sub.Text = assText(fields[i])
+		}
+		if err != nil {
+			return subtitle.Subtitle{}, err
+		}
+	}
+	return sub, nil
+}
+
+// assText removes override blocks from ASS text, and converts its line
+//  breaks, hard spaces and escaped characters (\{, \} and a backslash
+//  followed by a word joiner, as written by cabiria).
+func assText(text string) string {
+	var result strings.Builder
+	runes := []rune(text)
+	depth := 0
+	for i := 0; i < len(runes); i++ {
+		elem := runes[i]
+		switch {
+		case elem == '{':
+			depth++
+		case elem == '}' && depth > 0:
+			depth--
+		case depth > 0:
+			// Inside an override block
+		case elem == '\\' && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case 'N', 'n':
+				result.WriteRune('\n')
+			case 'h':
+				result.WriteRune(' ')
+			case '{', '}':
+				result.WriteRune(runes[i])
+			case '\u2060':
+				result.WriteRune('\\')
+			default:
+				// Not an escape
+				result.WriteRune('\\')
+				i--
+			}
+		default:
+			result.WriteRune(elem)
+		}
+	}
+	return result.String()
+}
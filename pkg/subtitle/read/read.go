@@ -0,0 +1,153 @@
+package read
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	"github.com/liampulles/cabiria/pkg/subtitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+// Reader loads the subtitles at path in some format, converting them from
+//  encoding (see file.Encodings), or from the detected encoding if it is
+//  empty. Problems which could be worked around are returned as warnings.
+type Reader func(path string, encoding string) ([]subtitle.Subtitle, []Warning, error)
+
+// Warning is a problem in a subtitle file which was worked around.
+type Warning struct {
+	// Line is the line number of the problem, from 1.
+	Line    int
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+// Formats maps the file extensions understood by Subtitles to the Reader for
+//  that format.
+var Formats = map[string]Reader{
+	".srt": SRT,
+	".vtt": WebVTT,
+	".ass": ASS,
+	".ssa": ASS,
+	".sub": SubViewer,
+}
+
+// Subtitles loads the subtitles at path, in the format given by the
+//  extension of path (see Formats), or failing that by its contents. The
+//  text is converted from encoding, or from the detected encoding if it is
+//  empty.
+func Subtitles(path string, encoding string) ([]subtitle.Subtitle, []Warning, error) {
+	reader, err := forPath(path, encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader(path, encoding)
+}
+
+// ForPath finds the Reader for the format given by the extension of path. If
+//  the extension is not known (e.g. ".txt"), the start of the file is
+//  examined instead.
+func ForPath(path string) (Reader, error) {
+	return forPath(path, "")
+}
+
+// forPath is like ForPath, but reads the start of the file from encoding,
+//  so that an encoding which was given (or already detected) is used.
+func forPath(path string, encoding string) (Reader, error) {
+	reader, ok := Formats[strings.ToLower(filepath.Ext(path))]
+	if ok {
+		return reader, nil
+	}
+	lines, err := file.ReadLinesFromEncodedTextFile(path, encoding)
+	if err != nil {
+		return nil, err
+	}
+	reader = detect(lines)
+	if reader == nil {
+		return nil, fmt.Errorf("cannot tell what format the subtitles in %s are in. Use one of the extensions: %s",
+			path, strings.Join(extensions(), ", "))
+	}
+	return reader, nil
+}
+
+// detect guesses the format of subtitles from their first lines.
+func detect(lines []string) Reader {
+	checked := 0
+	for i, line := range lines {
+		line = cleanLine(line, i)
+		if line == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "WEBVTT"):
+			return WebVTT
+		case strings.EqualFold(line, "[Script Info]"):
+			return ASS
+		case strings.EqualFold(line, "[INFORMATION]") || subViewerTimingPattern.MatchString(line):
+			return SubViewer
+		case srtTimingPattern.MatchString(line):
+			return SRT
+		}
+		// The timing of an SRT follows the index
+		checked++
+		if checked > 2 {
+			break
+		}
+	}
+	return nil
+}
+
+// formattedSubtitle creates a subtitle with SRT style text (see
+//  style.ParseSRTText), keeping its inline formatting. unescape, if not nil,
+//  is applied to the text once the tags are removed.
+func formattedSubtitle(start, end time.Time, text string, unescape func(string) string) subtitle.Subtitle {
+	spans := style.ParseSRTText(text)
+	if unescape != nil {
+		for i := range spans {
+			spans[i].Text = unescape(spans[i].Text)
+		}
+	}
+	sub := subtitle.Subtitle{
+		StartTime: start,
+		EndTime:   end,
+		Text:      style.PlainText(spans),
+	}
+	if style.Formatted(spans) {
+		sub.Spans = spans
+	}
+	return sub
+}
+
+// cleanLine removes the carriage return of a Windows line ending, and the
+//  byte order mark from the first line, if present.
+func cleanLine(line string, index int) string {
+	if index == 0 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+	return strings.TrimSuffix(line, "\r")
+}
+
+// parseClock parses a timestamp of the form [h:]mm:ss.fff, with any number
+//  of fractional digits (e.g. 0:01:02.50 in ASS, or 01:02.500 in WebVTT).
+func parseClock(clock string) (time.Time, error) {
+	if strings.Count(clock, ":") == 1 {
+		clock = "0:" + clock
+	}
+	return cabiriaTime.FromSRTTimecode(clock)
+}
+
+func extensions() []string {
+	var result []string
+	for ext := range Formats {
+		result = append(result, ext)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -2,107 +2,143 @@ package read
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/liampulles/cabiria/pkg/intertitle"
-	"github.com/liampulles/cabiria/pkg/subtitle/style"
-
 	"github.com/liampulles/cabiria/pkg/file"
 	"github.com/liampulles/cabiria/pkg/subtitle"
 	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
 )
 
-type lineType int
-
-const (
-	index lineType = iota
-	timecodes
-	text
-	blank
+var (
+	srtIndexPattern  = regexp.MustCompile(`^\s*\d+\s*$`)
+	srtTimingPattern = regexp.MustCompile(`^\s*([\d:.,]+)\s*-->\s*([\d:.,]+)`)
 )
 
 // SRT loads the SRT pointed to by path into the associated Subtitle slice.
-func SRT(path string) ([]subtitle.Subtitle, error) {
-	lines, err := file.ReadLinesFromTextFile(path)
+//  A subtitle starts at its timing line (optionally after an index), rather
+//  than after a blank line, so that missing blank lines and text which
+//  looks like an index (e.g. a year) are read correctly. Problems which can
+//  be worked around (e.g. missing or out of order indices) are returned as
+//  warnings. Inline formatting (<i>, <b>, <u> and <font color>) is kept.
+func SRT(path string, encoding string) ([]subtitle.Subtitle, []Warning, error) {
+	lines, err := file.ReadLinesFromEncodedTextFile(path, encoding)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	for i := range lines {
+		lines[i] = cleanLine(lines[i], i)
 	}
 
 	var subs []subtitle.Subtitle
-	var currentStart time.Time
-	var currentEnd time.Time
-	var currentLines []string
-	lastLineType := blank
-	for _, line := range lines {
-		currentLineType := getLineType(line, lastLineType)
-		switch currentLineType {
-		case blank, index:
-			subs = closeAndAddCurrent(currentStart, currentEnd, currentLines, subs)
-			currentLines = nil
-		case timecodes:
-			currentStart, currentEnd, err = getTimecodes(line)
-			if err != nil {
-				return nil, err
+	var warnings []Warning
+	warn := func(index int, format string, args ...interface{}) {
+		warnings = append(warnings, Warning{Line: index + 1, Message: fmt.Sprintf(format, args...)})
+	}
+	lastIndex := 0
+	for i := 0; i < len(lines); {
+		if strings.TrimSpace(lines[i]) == "" {
+			i++
+			continue
+		}
+		timing, ok := srtCueStart(lines, i)
+		if !ok {
+			warn(i, "ignoring text outside of a subtitle: %q", lines[i])
+			i++
+			continue
+		}
+
+		// Indices are not needed, but a mistake in them may be a sign of
+		//  something else wrong
+		if timing == i {
+			warn(i, "subtitle has no index")
+			lastIndex++
+		} else {
+			index, _ := strconv.Atoi(strings.TrimSpace(lines[i]))
+			if index != lastIndex+1 {
+				warn(i, "subtitle index %d is out of order, expected %d", index, lastIndex+1)
 			}
-		case text:
-			currentLines = append(currentLines, line)
+			lastIndex = index
+		}
+
+		start, end, err := srtTimecodes(lines[timing])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", path, timing+1, err)
+		}
+		if end.Before(start) {
+			warn(timing, "subtitle ends before it starts")
+		}
+
+		var text []string
+		i, text = srtText(lines, timing+1, warn)
+		if len(text) == 0 {
+			warn(timing, "ignoring subtitle with no text")
+			continue
 		}
-		lastLineType = currentLineType
+		subs = append(subs, formattedSubtitle(start, end, strings.Join(text, "\n"), nil))
 	}
-	subs = closeAndAddCurrent(currentStart, currentEnd, currentLines, subs)
-	return subs, nil
+	return subs, warnings, nil
 }
 
-func getLineType(line string, last lineType) lineType {
-	if strings.TrimSpace(line) == "" {
-		return blank
+// srtCueStart checks whether a subtitle starts at line i, either with an
+//  index followed by a timing, or with the timing alone. The line of the
+//  timing is returned.
+func srtCueStart(lines []string, i int) (int, bool) {
+	if srtTimingPattern.MatchString(lines[i]) {
+		return i, true
 	}
-	if last == blank {
-		return index
+	if srtIndexPattern.MatchString(lines[i]) && i+1 < len(lines) && srtTimingPattern.MatchString(lines[i+1]) {
+		return i + 1, true
 	}
-	if last == index {
-		return timecodes
-	}
-	if last == timecodes {
-		return text
-	}
-	if last == text {
-		return text
-	}
-	return -1
+	return -1, false
 }
 
-func closeAndAddCurrent(start, end time.Time, lines []string, subs []subtitle.Subtitle) []subtitle.Subtitle {
-	if lines == nil {
-		return subs
-	}
+// srtText reads the text of a subtitle from line i, until the start of the
+//  next subtitle. Blank lines followed by more text are skipped. The line
+//  after the text is returned.
+func srtText(lines []string, i int, warn func(int, string, ...interface{})) (int, []string) {
+	var text []string
+	for i < len(lines) {
+		if _, ok := srtCueStart(lines, i); ok {
+			if len(text) > 0 {
+				warn(i, "missing blank line before subtitle")
+			}
+			return i, text
+		}
+		if strings.TrimSpace(lines[i]) != "" {
+			text = append(text, lines[i])
+			i++
+			continue
+		}
 
-	joined := strings.Join(lines, "\n")
-	joined = style.RemoveStylesFromSRTText(joined)
-	new := subtitle.Subtitle{
-		StartTime: start,
-		EndTime:   end,
-		Text:      joined,
-		Style:     intertitle.Style{},
+		// A blank line normally ends the text, unless more text follows
+		next := i
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next == len(lines) || len(text) == 0 {
+			return next, text
+		}
+		if _, ok := srtCueStart(lines, next); ok {
+			return next, text
+		}
+		warn(i, "blank line within subtitle text")
+		i = next
 	}
-
-	subs = append(subs, new)
-	return subs
+	return i, text
 }
 
-func getTimecodes(line string) (time.Time, time.Time, error) {
-	fields := strings.Fields(line)
-	if len(fields) < 3 {
-		return time.Now(), time.Now(), fmt.Errorf("the timecode line needs at least 3 fields in a SRT file. Received: %s", line)
-	}
-	start, err := cabiriaTime.FromSRTTimecode(fields[0])
+func srtTimecodes(line string) (time.Time, time.Time, error) {
+	match := srtTimingPattern.FindStringSubmatch(line)
+	start, err := cabiriaTime.FromSRTTimecode(match[1])
 	if err != nil {
-		return time.Now(), time.Now(), err
+		return time.Time{}, time.Time{}, err
 	}
-	end, err := cabiriaTime.FromSRTTimecode(fields[2])
+	end, err := cabiriaTime.FromSRTTimecode(match[2])
 	if err != nil {
-		return time.Now(), time.Now(), err
+		return time.Time{}, time.Time{}, err
 	}
-	return start, end, err
+	return start, end, nil
 }
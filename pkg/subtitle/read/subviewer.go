@@ -0,0 +1,77 @@
+package read
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	"github.com/liampulles/cabiria/pkg/subtitle"
+)
+
+var subViewerTimingPattern = regexp.MustCompile(`^(\d+:\d{2}:\d{2}\.\d+)\s*,\s*(\d+:\d{2}:\d{2}\.\d+)$`)
+
+// SubViewer loads the SubViewer 2.0 file pointed to by path, e.g.:
+//
+//    [INFORMATION]
+//    [TITLE]Nosferatu
+//    [END INFORMATION]
+//    [SUBTITLE]
+//    00:02:31.56,00:02:37.16
+//    First line[br]Second line
+//
+//  Header lines (in brackets) are ignored, and [br] is a line break.
+func SubViewer(path string, encoding string) ([]subtitle.Subtitle, []Warning, error) {
+	lines, err := file.ReadLinesFromEncodedTextFile(path, encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var subs []subtitle.Subtitle
+	for _, block := range blocks(lines) {
+		timing := -1
+		for i, elem := range block.lines {
+			if subViewerTimingPattern.MatchString(strings.TrimSpace(elem)) {
+				timing = i
+				break
+			}
+		}
+		// Only header lines may come before the timing
+		header := block.lines
+		if timing >= 0 {
+			header = block.lines[:timing]
+		}
+		for i, elem := range header {
+			if !strings.HasPrefix(strings.TrimSpace(elem), "[") {
+				return nil, nil, fmt.Errorf("%s:%d: expected a timing like 00:00:01.00,00:00:02.00, but found %q", path, block.number+i, elem)
+			}
+		}
+		if timing < 0 {
+			continue
+		}
+		sub, err := subViewerSubtitle(block.lines[timing], block.lines[timing+1:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", path, block.number+timing, err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil, nil
+}
+
+func subViewerSubtitle(timing string, text []string) (subtitle.Subtitle, error) {
+	match := subViewerTimingPattern.FindStringSubmatch(strings.TrimSpace(timing))
+	start, err := parseClock(match[1])
+	if err != nil {
+		return subtitle.Subtitle{}, err
+	}
+	end, err := parseClock(match[2])
+	if err != nil {
+		return subtitle.Subtitle{}, err
+	}
+	joined := strings.Join(text, "\n")
+	return subtitle.Subtitle{
+		StartTime: start,
+		EndTime:   end,
+		Text:      strings.Replace(joined, "[br]", "\n", -1),
+	}, nil
+}
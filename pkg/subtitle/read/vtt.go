@@ -0,0 +1,95 @@
+package read
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	"github.com/liampulles/cabiria/pkg/subtitle"
+)
+
+// WebVTT loads the cues of the WebVTT file pointed to by path. Cue settings,
+//  NOTE, STYLE and REGION blocks are ignored. Inline formatting (<i>, <b> and
+//  <u>) is kept, and other tags (e.g. <v Name>) are removed from the text.
+func WebVTT(path string, encoding string) ([]subtitle.Subtitle, []Warning, error) {
+	lines, err := file.ReadLinesFromEncodedTextFile(path, encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lines) == 0 || !strings.HasPrefix(cleanLine(lines[0], 0), "WEBVTT") {
+		return nil, nil, fmt.Errorf("%s:1: a WebVTT file must start with WEBVTT", path)
+	}
+
+	var subs []subtitle.Subtitle
+	// The first block is the header
+	for _, block := range blocks(lines)[1:] {
+		if isVTTMetadata(block.lines[0]) {
+			continue
+		}
+		// An optional identifier may come before the timing
+		timing := 0
+		if !strings.Contains(block.lines[0], "-->") {
+			timing = 1
+		}
+		if timing >= len(block.lines) || !strings.Contains(block.lines[timing], "-->") {
+			return nil, nil, fmt.Errorf("%s:%d: expected a cue timing, but found %q", path, block.number, block.lines[0])
+		}
+		sub, err := vttCue(block.lines[timing], block.lines[timing+1:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", path, block.number+timing, err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil, nil
+}
+
+func vttCue(timing string, text []string) (subtitle.Subtitle, error) {
+	fields := strings.Fields(timing)
+	if len(fields) < 3 || fields[1] != "-->" {
+		return subtitle.Subtitle{}, fmt.Errorf("invalid cue timing %q", timing)
+	}
+	start, err := parseClock(fields[0])
+	if err != nil {
+		return subtitle.Subtitle{}, err
+	}
+	end, err := parseClock(fields[2])
+	if err != nil {
+		return subtitle.Subtitle{}, err
+	}
+	return formattedSubtitle(start, end, strings.Join(text, "\n"), html.UnescapeString), nil
+}
+
+func isVTTMetadata(line string) bool {
+	for _, elem := range []string{"NOTE", "STYLE", "REGION"} {
+		if line == elem || strings.HasPrefix(line, elem+" ") || strings.HasPrefix(line, elem+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+// block is a run of non-blank lines, starting at line number (from 1).
+type block struct {
+	number int
+	lines  []string
+}
+
+// blocks splits lines into runs separated by blank lines.
+func blocks(lines []string) []block {
+	var result []block
+	var current *block
+	for i, line := range lines {
+		line = cleanLine(line, i)
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			result = append(result, block{number: i + 1})
+			current = &result[len(result)-1]
+		}
+		current.lines = append(current.lines, line)
+	}
+	return result
+}
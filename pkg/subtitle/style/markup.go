@@ -0,0 +1,211 @@
+package style
+
+import (
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Span is a run of subtitle text with the same inline formatting.
+type Span struct {
+	Text      string
+	Italic    bool
+	Bold      bool
+	Underline bool
+	// Color is the color of the text, or nil if it is not set.
+	Color color.Color
+}
+
+// Formatted returns true if the span has any formatting, otherwise false.
+func (s Span) Formatted() bool {
+	return s.Italic || s.Bold || s.Underline || s.Color != nil
+}
+
+// sameFormat returns true if s and other are formatted alike.
+func (s Span) sameFormat(other Span) bool {
+	if s.Italic != other.Italic || s.Bold != other.Bold || s.Underline != other.Underline {
+		return false
+	}
+	if s.Color == nil || other.Color == nil {
+		return s.Color == nil && other.Color == nil
+	}
+	r1, g1, b1, a1 := s.Color.RGBA()
+	r2, g2, b2, a2 := other.Color.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+var srtColorPattern = regexp.MustCompile(`(?i)\bcolor\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"']+))`)
+
+// namedColors are the basic HTML color names, which may be used in font
+//  tags instead of hex.
+var namedColors = map[string]color.RGBA{
+	"black":   {0x00, 0x00, 0x00, 0xFF},
+	"silver":  {0xC0, 0xC0, 0xC0, 0xFF},
+	"gray":    {0x80, 0x80, 0x80, 0xFF},
+	"grey":    {0x80, 0x80, 0x80, 0xFF},
+	"white":   {0xFF, 0xFF, 0xFF, 0xFF},
+	"maroon":  {0x80, 0x00, 0x00, 0xFF},
+	"red":     {0xFF, 0x00, 0x00, 0xFF},
+	"purple":  {0x80, 0x00, 0x80, 0xFF},
+	"fuchsia": {0xFF, 0x00, 0xFF, 0xFF},
+	"magenta": {0xFF, 0x00, 0xFF, 0xFF},
+	"green":   {0x00, 0x80, 0x00, 0xFF},
+	"lime":    {0x00, 0xFF, 0x00, 0xFF},
+	"olive":   {0x80, 0x80, 0x00, 0xFF},
+	"yellow":  {0xFF, 0xFF, 0x00, 0xFF},
+	"navy":    {0x00, 0x00, 0x80, 0xFF},
+	"blue":    {0x00, 0x00, 0xFF, 0xFF},
+	"teal":    {0x00, 0x80, 0x80, 0xFF},
+	"aqua":    {0x00, 0xFF, 0xFF, 0xFF},
+	"cyan":    {0x00, 0xFF, 0xFF, 0xFF},
+}
+
+// ParseSRTText splits SRT text into spans by its <i>, <b>, <u> and
+//  <font color="..."> tags, e.g. "a <i>b</i>" => "a ", "b" (italic). Tags
+//  may be nested, and are closed at the end of the text if left open. Other
+//  tags (and closing tags which were never opened) are removed. Adjacent
+//  spans with the same formatting are joined.
+func ParseSRTText(text string) []Span {
+	var spans []Span
+	var italic, bold, underline int
+	var colors []color.Color
+	current := func() Span {
+		span := Span{Italic: italic > 0, Bold: bold > 0, Underline: underline > 0}
+		if len(colors) > 0 {
+			span.Color = colors[len(colors)-1]
+		}
+		return span
+	}
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		span := current()
+		if len(spans) > 0 && spans[len(spans)-1].sameFormat(span) {
+			spans[len(spans)-1].Text += s
+			return
+		}
+		span.Text = s
+		spans = append(spans, span)
+	}
+	closeTag := func(count *int) {
+		if *count > 0 {
+			*count--
+		}
+	}
+
+	for text != "" {
+		start := strings.Index(text, "<")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], ">")
+		if end < 0 {
+			break
+		}
+		write(text[:start])
+		name, attributes := srtTag(text[start+1 : start+end])
+		text = text[start+end+1:]
+
+		switch name {
+		case "i":
+			italic++
+		case "/i":
+			closeTag(&italic)
+		case "b":
+			bold++
+		case "/b":
+			closeTag(&bold)
+		case "u":
+			underline++
+		case "/u":
+			closeTag(&underline)
+		case "font":
+			// Keep the current color if the font tag does not give one, so
+			//  that its closing tag still matches
+			col := current().Color
+			if parsed, ok := parseSRTColor(srtColor(attributes)); ok {
+				col = parsed
+			}
+			colors = append(colors, col)
+		case "/font":
+			if len(colors) > 0 {
+				colors = colors[:len(colors)-1]
+			}
+		}
+	}
+	write(text)
+	return spans
+}
+
+// PlainText joins the text of spans, without any formatting.
+func PlainText(spans []Span) string {
+	var result strings.Builder
+	for _, elem := range spans {
+		result.WriteString(elem.Text)
+	}
+	return result.String()
+}
+
+// Formatted returns true if any of spans has formatting, otherwise false.
+func Formatted(spans []Span) bool {
+	for _, elem := range spans {
+		if elem.Formatted() {
+			return true
+		}
+	}
+	return false
+}
+
+// srtTag splits the inside of a tag into its lowercase name (starting with
+//  "/" if it is a closing tag) and its attributes.
+func srtTag(inside string) (string, string) {
+	inside = strings.TrimSpace(inside)
+	closing := strings.HasPrefix(inside, "/")
+	if closing {
+		inside = strings.TrimSpace(inside[1:])
+	}
+	name, attributes := inside, ""
+	if space := strings.IndexAny(inside, " \t"); space >= 0 {
+		name, attributes = inside[:space], inside[space+1:]
+	}
+	name = strings.ToLower(name)
+	if closing {
+		return "/" + name, ""
+	}
+	return name, attributes
+}
+
+// srtColor finds the value of the color attribute of a font tag, e.g.
+//  `color="#FF0000"` => "#FF0000", or "" if there is none.
+func srtColor(attributes string) string {
+	match := srtColorPattern.FindStringSubmatch(attributes)
+	if match == nil {
+		return ""
+	}
+	return match[1] + match[2] + match[3]
+}
+
+// parseSRTColor parses a color given as hex (e.g. "#FF8000", "ff8000") or
+//  by a basic HTML name (e.g. "red").
+func parseSRTColor(value string) (color.Color, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if named, ok := namedColors[value]; ok {
+		return named, true
+	}
+	value = strings.TrimPrefix(value, "#")
+	if len(value) != 6 {
+		return nil, false
+	}
+	rgb, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	return color.RGBA{
+		R: uint8(rgb >> 16),
+		G: uint8(rgb >> 8),
+		B: uint8(rgb),
+		A: 0xFF,
+	}, true
+}
@@ -1,43 +1,34 @@
 package style
 
-import (
-	"strings"
-)
-
-// Style defines the aesthetic aspects of a piece of text when rendered
+// Style defines the aesthetic aspects of a piece of text when rendered.
+//  Alignment follows the numeric keypad, e.g. 5 is centered, 2 is the bottom
+//  center.
 type Style struct {
-	FontName string
-	FontSize uint
+	FontName       string
+	FontSize       uint
+	Outline        uint
+	Alignment      uint
+	MarginLeft     uint
+	MarginRight    uint
+	MarginVertical uint
 }
 
-type styleTagPos struct {
-	Start int
-	End   int
+// Default returns the Style used unless otherwise configured: a thick outline
+//  (i.e. an opaque box, as is typical of intertitles) in the center.
+func Default(fontName string, fontSize uint) Style {
+	return Style{
+		FontName:       fontName,
+		FontSize:       fontSize,
+		Outline:        1000,
+		Alignment:      5,
+		MarginLeft:     10,
+		MarginRight:    10,
+		MarginVertical: 10,
+	}
 }
 
 // RemoveStylesFromSRTText removes any formatting from the given string such that
 //  e.g. "<...>some text</...>" => "some text"
-// TODO: At the moment, encoding styles is more complexity then it is worth - so
-// rather than storing the styles in SRT text, we just seek to remove any styling.
-// One day...?
 func RemoveStylesFromSRTText(text string) string {
-	text2 := text
-	lastLength := len(text2) + 1
-	for lastLength != len(text2) {
-		lastLength = len(text2)
-		text2 = removeATag(text2)
-	}
-	return text2
-}
-
-func removeATag(text string) string {
-	startPos := strings.Index(text, "<")
-	if startPos < 0 {
-		return text
-	}
-	endPos := strings.Index(text[startPos:], ">")
-	if endPos < 0 {
-		return text
-	}
-	return text[:startPos] + text[endPos+startPos+1:]
+	return PlainText(ParseSRTText(text))
 }
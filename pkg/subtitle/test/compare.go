@@ -5,9 +5,11 @@ import (
 	"math"
 	"time"
 
+	imageTest "github.com/liampulles/cabiria/pkg/image/test"
 	"github.com/liampulles/cabiria/pkg/intertitle/test"
 
 	"github.com/liampulles/cabiria/pkg/subtitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
 )
 
 // CompareSubtitles will return an error if something about two slices of subtitles
@@ -47,6 +49,9 @@ func CompareSubtitle(actual, expected subtitle.Subtitle) error {
 	if !veryClose(actual.EndTime, expected.EndTime) {
 		return fmt.Errorf("endTime differs: Actual: %s, Expected: %s", actual.EndTime, expected.EndTime)
 	}
+	if err := CompareSpans(actual.Spans, expected.Spans); err != nil {
+		return fmt.Errorf("Spans differ: %v", err)
+	}
 	// Style
 	if err := test.CompareStyle(actual.Style, expected.Style); err != nil {
 		return fmt.Errorf("Styles differ: %v", err)
@@ -54,6 +59,33 @@ func CompareSubtitle(actual, expected subtitle.Subtitle) error {
 	return nil
 }
 
+// CompareSpans will return an error if something about two slices of spans
+//  is not the same. Otherwise, nil is returned.
+func CompareSpans(actual, expected []style.Span) error {
+	if len(actual) != len(expected) {
+		return fmt.Errorf("different lengths: Actual: %v, Expected %v", actual, expected)
+	}
+	for i, actualI := range actual {
+		expectedI := expected[i]
+		if actualI.Text != expectedI.Text ||
+			actualI.Italic != expectedI.Italic ||
+			actualI.Bold != expectedI.Bold ||
+			actualI.Underline != expectedI.Underline {
+			return fmt.Errorf("element %d differs: Actual: %v, Expected: %v", i, actualI, expectedI)
+		}
+		if actualI.Color == nil || expectedI.Color == nil {
+			if actualI.Color != nil || expectedI.Color != nil {
+				return fmt.Errorf("nil color on element %d: Actual: %v, Expected: %v", i, actualI.Color, expectedI.Color)
+			}
+			continue
+		}
+		if err := imageTest.CompareColor(actualI.Color, expectedI.Color); err != nil {
+			return fmt.Errorf("colors differ on element %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
 func veryClose(actual, expected time.Time) bool {
 	return math.Abs(float64(actual.Sub(expected))) < float64(50*time.Nanosecond)
 }
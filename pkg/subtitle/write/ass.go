@@ -3,6 +3,8 @@ package write
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/liampulles/cabiria/pkg/file"
@@ -21,19 +23,26 @@ type VideoInformation interface {
 	VideoPath() string
 	VideoWidth() int
 	VideoHeight() int
+	// VideoSampleAspectRatio is the width:height ratio of a single pixel,
+	//  or 0.0 if unknown.
+	VideoSampleAspectRatio() float64
+	// VideoDisplayAspectRatio is the width:height ratio of the video as it
+	//  should be displayed, or 0.0 if unknown.
+	VideoDisplayAspectRatio() float64
 }
 
 // ASS saves subtitles with a given style to ASS format at path
 func ASS(subs []subtitle.Subtitle, sty style.Style, vidInfo VideoInformation, path string) error {
 	text := ""
-	text += assHeader(vidInfo.VideoPath(), vidInfo.VideoWidth(), vidInfo.VideoHeight())
+	text += assHeader(vidInfo)
 	text += assStyles(sty)
 	text += assEvents(subs)
 
 	return file.SaveTextToFile(path, text)
 }
 
-func assHeader(videoName string, videoWidth, videoHeight int) string {
+func assHeader(vidInfo VideoInformation) string {
+	playResX, playResY, aspectRatio := assPlayRes(vidInfo)
 	return fmt.Sprintf(`[Script Info]
 ; Script generated by %s %s
 ; %s
@@ -42,7 +51,7 @@ ScriptType: v4.00+
 WrapStyle: 0
 PlayResX: %d
 PlayResY: %d
-Video Aspect Ratio: 0
+Video Aspect Ratio: %s
 Video Zoom: 6
 Video Position: 0
 Collisions: Normal
@@ -52,15 +61,42 @@ Collisions: Normal
 		meta.ProgramVersion,
 		meta.ProgramURL,
 		meta.ProgramName,
-		videoName,
-		videoWidth,
-		videoHeight)
+		vidInfo.VideoPath(),
+		playResX,
+		playResY,
+		aspectRatio)
+}
+
+// Renderers stretch PlayRes over the displayed video, so for anamorphic video
+//  we use the display (square pixel) width, else intertitles get stretched.
+//  We also give the display aspect ratio, for editors like Aegisub.
+func assPlayRes(vidInfo VideoInformation) (int, int, string) {
+	width, height := vidInfo.VideoWidth(), vidInfo.VideoHeight()
+	displayWidth := assDisplayWidth(vidInfo)
+	if displayWidth <= 0 || displayWidth == width || height <= 0 {
+		return width, height, "0"
+	}
+	dar := float64(displayWidth) / float64(height)
+	return displayWidth, height, "c" + strconv.FormatFloat(dar, 'f', 6, 64)
+}
+
+// assDisplayWidth gives the width of the video with square pixels, from its
+//  sample aspect ratio, else its display aspect ratio (some videos only
+//  declare the latter), or 0 if neither is known.
+func assDisplayWidth(vidInfo VideoInformation) int {
+	if sar := vidInfo.VideoSampleAspectRatio(); sar > 0.0 {
+		return int(math.Round(float64(vidInfo.VideoWidth()) * sar))
+	}
+	if dar := vidInfo.VideoDisplayAspectRatio(); dar > 0.0 {
+		return int(math.Round(float64(vidInfo.VideoHeight()) * dar))
+	}
+	return 0
 }
 
 func assStyles(sty style.Style) string {
 	return fmt.Sprintf(`[V4+ Styles]
 Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
-Style: cabiria,%s,%d,%s,%s,%s,%s,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+Style: cabiria,%s,%d,%s,%s,%s,%s,0,0,0,0,100,100,0,0,3,%d,0,%d,%d,%d,%d,1
 
 `,
 		sty.FontName,
@@ -68,7 +104,12 @@ Style: cabiria,%s,%d,%s,%s,%s,%s,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
 		assColor(color.White, false),
 		assColor(color.Transparent, true),
 		assColor(color.Black, true),
-		assColor(color.Transparent, false))
+		assColor(color.Transparent, false),
+		sty.Outline,
+		sty.Alignment,
+		sty.MarginLeft,
+		sty.MarginRight,
+		sty.MarginVertical)
 }
 
 func assColor(col color.Color, withAlpha bool) string {
@@ -98,14 +139,80 @@ Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
 }
 
 func assDialogueLine(sub subtitle.Subtitle) string {
-	return fmt.Sprintf("Dialogue: 0,%s,%s,cabiria,,0000,0000,0000,,{\\c%s&\\3c%s&}%s\n",
+	return fmt.Sprintf("Dialogue: 0,%s,%s,cabiria,,0000,0000,0000,,%s\n",
 		cabiriaTime.ToASSTimecode(sub.StartTime),
 		cabiriaTime.ToASSTimecode(sub.EndTime),
-		assColor(sub.Style.ForegroundColor, false),
-		assColor(sub.Style.BackgroundColor, false),
-		replaceNewlineWithSlashN(sub.Text))
+		assText(sub))
+}
+
+// assText renders the text of sub, starting with its intertitle colors, and
+//  with override tags wherever its inline formatting changes.
+func assText(sub subtitle.Subtitle) string {
+	foreground := assColor(sub.Style.ForegroundColor, false)
+	overrides := fmt.Sprintf("\\c%s&\\3c%s&",
+		foreground,
+		assColor(sub.Style.BackgroundColor, false))
+	spans := sub.Spans
+	if spans == nil {
+		spans = []style.Span{{Text: sub.Text}}
+	}
+
+	var result strings.Builder
+	var last style.Span
+	for _, elem := range spans {
+		overrides += assOverrides(last, elem, foreground)
+		if overrides != "" {
+			result.WriteString("{" + overrides + "}")
+			overrides = ""
+		}
+		result.WriteString(assEscape(elem.Text))
+		last = elem
+	}
+	return result.String()
 }
 
-func replaceNewlineWithSlashN(lined string) string {
-	return strings.ReplaceAll(lined, "\n", "\\N")
+// assOverrides gives the override tags needed to change from the formatting
+//  of one span to the next. Text without a color of its own uses foreground.
+func assOverrides(from, to style.Span, foreground string) string {
+	result := assToggle("i", from.Italic, to.Italic) +
+		assToggle("b", from.Bold, to.Bold) +
+		assToggle("u", from.Underline, to.Underline)
+	fromColor, toColor := foreground, foreground
+	if from.Color != nil {
+		fromColor = assColor(from.Color, false)
+	}
+	if to.Color != nil {
+		toColor = assColor(to.Color, false)
+	}
+	if fromColor != toColor {
+		result += "\\c" + toColor + "&"
+	}
+	return result
+}
+
+func assToggle(tag string, from, to bool) string {
+	switch {
+	case to && !from:
+		return "\\" + tag + "1"
+	case from && !to:
+		return "\\" + tag + "0"
+	}
+	return ""
+}
+
+// assEscaper makes text show as is in ASS. Braces would otherwise start
+//  override blocks, so are escaped as \{ and \}, which libass (e.g. in mpv or
+//  VLC) shows as braces, though VSFilter shows the backslash too. A backslash
+//  could start an escape (e.g. "\N" in OCR'd text), so is followed by an
+//  invisible word joiner (U+2060), which stops it combining with the next
+//  character and which read.ASS drops again. Newlines become \N.
+var assEscaper = strings.NewReplacer(
+	`\`, "\\\u2060",
+	"{", `\{`,
+	"}", `\}`,
+	"\n", `\N`,
+)
+
+func assEscape(text string) string {
+	return assEscaper.Replace(text)
 }
@@ -0,0 +1,105 @@
+package write
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+	"github.com/liampulles/cabiria/pkg/subtitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
+
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+// SRT saves subtitles to SRT format at path. Inline formatting (see
+//  subtitle.Subtitle.Spans) is kept as <i>, <b>, <u> and <font color="...">
+//  tags. Any other style information is discarded.
+func SRT(subs []subtitle.Subtitle, path string) error {
+	text := ""
+	for i, sub := range subs {
+		text += srtEntry(i+1, sub)
+	}
+
+	return file.SaveTextToFile(path, text)
+}
+
+func srtEntry(index int, sub subtitle.Subtitle) string {
+	return fmt.Sprintf("%d\n%s --> %s\n%s\n\n",
+		index,
+		cabiriaTime.ToSRTTimecode(sub.StartTime),
+		cabiriaTime.ToSRTTimecode(sub.EndTime),
+		srtText(sub))
+}
+
+// srtText gives the text of sub with its spans tagged. Tags which carry on
+//  into the next span are left open, and tags are closed in the reverse of
+//  the order they were opened, e.g. "<i>a<b>b</b></i>".
+func srtText(sub subtitle.Subtitle) string {
+	if !style.Formatted(sub.Spans) {
+		return sub.Text
+	}
+
+	var result strings.Builder
+	var open []srtTag
+	for _, elem := range sub.Spans {
+		wanted := srtTags(elem)
+		// Close from the first open tag which is no longer wanted
+		for i, tag := range open {
+			if !containsTag(wanted, tag) {
+				for j := len(open) - 1; j >= i; j-- {
+					result.WriteString(open[j].closing())
+				}
+				open = open[:i]
+				break
+			}
+		}
+		for _, tag := range wanted {
+			if !containsTag(open, tag) {
+				result.WriteString(tag.opening)
+				open = append(open, tag)
+			}
+		}
+		result.WriteString(elem.Text)
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		result.WriteString(open[j].closing())
+	}
+	return result.String()
+}
+
+// srtTag is an SRT formatting tag, e.g. {"font", `<font color="#ff0000">`}.
+type srtTag struct {
+	name    string
+	opening string
+}
+
+func (st srtTag) closing() string {
+	return "</" + st.name + ">"
+}
+
+// srtTags gives the tags for the formatting of span, outermost first.
+func srtTags(span style.Span) []srtTag {
+	var result []srtTag
+	if span.Color != nil {
+		result = append(result, srtTag{"font", fmt.Sprintf("<font color=\"%s\">", cabiriaImage.HexColor(span.Color))})
+	}
+	for _, elem := range []struct {
+		name string
+		set  bool
+	}{{"i", span.Italic}, {"b", span.Bold}, {"u", span.Underline}} {
+		if elem.set {
+			result = append(result, srtTag{elem.name, "<" + elem.name + ">"})
+		}
+	}
+	return result
+}
+
+func containsTag(tags []srtTag, target srtTag) bool {
+	for _, elem := range tags {
+		if elem == target {
+			return true
+		}
+	}
+	return false
+}
@@ -6,9 +6,8 @@ import (
 )
 
 // ToASSTimecode formats a time as a timecode which is appropriate
-//  for use in an ASS file.
+//  for use in an ASS file. The hours keep counting past 24.
 func ToASSTimecode(t time.Time) string {
-	return fmt.Sprintf("%d", t.Hour()) +
-		t.Format(":04:05.") +
-		fmt.Sprintf("%03d", time.Duration(t.Nanosecond())/time.Millisecond)[:2]
+	hours, minutes, seconds, fraction := clock(t)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, seconds, int64(fraction/(10*time.Millisecond)))
 }
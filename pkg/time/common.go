@@ -1,6 +1,9 @@
 package time
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Min returns the time that is earliest.
 func Min(a, b time.Time) time.Time {
@@ -25,3 +28,42 @@ func Scale(t time.Time, origin time.Time, factor float64) time.Time {
 	newDist := time.Duration(float64(currentDist) * factor)
 	return origin.Add(newDist)
 }
+
+// FromDuration returns the time that is d after the start of a video.
+func FromDuration(d time.Duration) time.Time {
+	return time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC).Add(d)
+}
+
+// ToDuration returns how long after the start of a video t is. It is the
+//  inverse of FromDuration.
+func ToDuration(t time.Time) time.Duration {
+	return t.Sub(FromDuration(0))
+}
+
+// ToTimecode formats a time as a timecode such as "01:02:03.456", with
+//  digits (up to 9) digits of the seconds after the point, truncated. The
+//  hours keep counting past 24.
+func ToTimecode(t time.Time, digits int) string {
+	hours, minutes, seconds, fraction := clock(t)
+	if digits <= 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+	}
+	if digits > 9 {
+		digits = 9
+	}
+	for i := digits; i < 9; i++ {
+		fraction /= 10
+	}
+	return fmt.Sprintf("%02d:%02d:%02d.%0*d", hours, minutes, seconds, digits, int64(fraction))
+}
+
+// clock splits how long after the start of a video t is into hours, minutes,
+//  seconds and the fraction of a second. Unlike t.Hour(), the hours do not
+//  wrap at 24.
+func clock(t time.Time) (hours, minutes, seconds int64, fraction time.Duration) {
+	d := ToDuration(t)
+	return int64(d / time.Hour),
+		int64(d % time.Hour / time.Minute),
+		int64(d % time.Minute / time.Second),
+		d % time.Second
+}
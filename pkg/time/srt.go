@@ -1,20 +1,57 @@
 package time
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
-const srtTimecodeFormat = "15:04:05"
+var srtTimecodePattern = regexp.MustCompile(`^(\d+):(\d{1,2}):(\d{1,2})(?:[,.](\d+))?$`)
 
 // FromSRTTimecode translates the typical timecode found in an SRT file
-//  e.g. (1:23:45:678) into the corresponding Time
+//  e.g. (1:23:45,678) into the corresponding Time. A period may be used
+//  instead of the comma, the fraction of a second may have any number of
+//  digits (e.g. 1:23:45.5) or be left out, and the hours may be 24 or more.
 func FromSRTTimecode(timecode string) (time.Time, error) {
-	t, err := time.Parse(srtTimecodeFormat, timecode[:len(timecode)-4])
-	if err != nil {
-		return time.Now(), err
+	match := srtTimecodePattern.FindStringSubmatch(strings.TrimSpace(timecode))
+	if match == nil {
+		return time.Time{}, fmt.Errorf("invalid timecode %q", timecode)
 	}
-	milli, err := time.ParseDuration(timecode[len(timecode)-3:] + "ms")
-	if err != nil {
-		return time.Now(), err
+	var fields [3]int
+	for i := range fields {
+		value, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timecode %q: %v", timecode, err)
+		}
+		fields[i] = value
 	}
-	return t.Add(milli), nil
+	if fields[1] > 59 || fields[2] > 59 {
+		return time.Time{}, fmt.Errorf("invalid timecode %q: minutes and seconds must be below 60", timecode)
+	}
+
+	d := time.Duration(fields[0])*time.Hour +
+		time.Duration(fields[1])*time.Minute +
+		time.Duration(fields[2])*time.Second
+	// Only nanoseconds can be represented
+	fraction := match[4]
+	if len(fraction) > 9 {
+		fraction = fraction[:9]
+	}
+	if fraction != "" {
+		nanos, err := strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction)))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timecode %q: %v", timecode, err)
+		}
+		d += time.Duration(nanos)
+	}
+	return FromDuration(d), nil
+}
 
+// ToSRTTimecode formats a time as a timecode which is appropriate
+//  for use in an SRT file. The hours keep counting past 24.
+func ToSRTTimecode(t time.Time) string {
+	hours, minutes, seconds, fraction := clock(t)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, int64(fraction/time.Millisecond))
 }
@@ -0,0 +1,139 @@
+package video
+
+import (
+	"image"
+	"math"
+
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+)
+
+// DuplicateThreshold is the maximum difference (see image.Diff) between two
+//  consecutive frames for the later to be considered a duplicate.
+const DuplicateThreshold = 0.01
+
+// MaxCadenceCycle is the longest cycle of frames that DetectCadence will
+//  consider.
+const MaxCadenceCycle = 15
+
+// cadenceTolerance is how closely a cycle must match the proportion of
+//  duplicates for longer cycles to not be considered.
+const cadenceTolerance = 0.002
+
+// maxPulldownRun is the longest run of duplicates which could be due to
+//  pulldown. Longer runs are static shots (e.g. intertitles) and are ignored.
+const maxPulldownRun = 2
+
+// Cadence describes a repeating pattern of duplicate frames in a video, e.g.
+//  3:2 pulldown repeats one frame in every cycle of five.
+type Cadence struct {
+	Cycle   int
+	Repeats int
+}
+
+// FilmFPS estimates the frame rate of the original film, given the frame rate
+//  of the video stream. e.g. 29.97 FPS with 3:2 pulldown -> 23.976 FPS
+func (c Cadence) FilmFPS(streamFPS float64) float64 {
+	if c.Cycle <= 0 || c.Repeats >= c.Cycle {
+		return streamFPS
+	}
+	return streamFPS * float64(c.Cycle-c.Repeats) / float64(c.Cycle)
+}
+
+// DetectCadence finds the shortest cycle which explains the proportion of
+//  duplicates, an array which flags each frame that duplicates its
+//  predecessor. Static shots are ignored. If there are no repeats, a Cadence
+//  with a Cycle of 1 is returned.
+func DetectCadence(duplicates []bool) Cadence {
+	considered := 0
+	repeated := 0
+	for i, candidate := range pulldownCandidates(duplicates) {
+		if !candidate {
+			continue
+		}
+		considered++
+		if duplicates[i] {
+			repeated++
+		}
+	}
+	if considered == 0 {
+		return Cadence{Cycle: 1}
+	}
+	ratio := float64(repeated) / float64(considered)
+
+	best := Cadence{Cycle: 1}
+	bestError := math.Inf(1)
+	for cycle := 1; cycle <= MaxCadenceCycle; cycle++ {
+		repeats := int(math.Round(ratio * float64(cycle)))
+		err := math.Abs(float64(repeats)/float64(cycle) - ratio)
+		if err < bestError {
+			best = Cadence{Cycle: cycle, Repeats: repeats}
+			bestError = err
+		}
+		if err <= cadenceTolerance {
+			break
+		}
+	}
+	if best.Repeats == 0 {
+		return Cadence{Cycle: 1}
+	}
+	return best
+}
+
+// pulldownCandidates flags the frames which are not part of a static shot.
+func pulldownCandidates(duplicates []bool) []bool {
+	result := make([]bool, len(duplicates))
+	for i := 0; i < len(duplicates); {
+		if !duplicates[i] {
+			result[i] = true
+			i++
+			continue
+		}
+		end := i
+		for end < len(duplicates) && duplicates[end] {
+			end++
+		}
+		if end-i <= maxPulldownRun {
+			for j := i; j < end; j++ {
+				result[j] = true
+			}
+		}
+		i = end
+	}
+	return result
+}
+
+// CadenceDetector finds duplicate frames, given every frame of a video in
+//  order.
+type CadenceDetector struct {
+	last       image.Image
+	duplicates []bool
+}
+
+// Add compares frame with the previously added frame, and records whether it
+//  is a duplicate.
+func (cd *CadenceDetector) Add(frame image.Image) error {
+	diff := 0.0
+	if cd.last != nil {
+		var err error
+		diff, err = cabiriaImage.Diff(cd.last, frame)
+		if err != nil {
+			return err
+		}
+	}
+	cd.AddDiff(diff)
+	cd.last = frame
+	return nil
+}
+
+// AddDiff is like Add, but is given the difference (see image.Diff) between
+//  the frame and the previous frame, for when it has already been computed.
+//  The difference given for the first frame is ignored.
+func (cd *CadenceDetector) AddDiff(diff float64) {
+	duplicate := len(cd.duplicates) > 0 && diff <= DuplicateThreshold
+	cd.duplicates = append(cd.duplicates, duplicate)
+}
+
+// Cadence detects the Cadence of the frames added so far.
+func (cd *CadenceDetector) Cadence() Cadence {
+	return DetectCadence(cd.duplicates)
+}
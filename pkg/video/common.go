@@ -1,20 +1,42 @@
 package video
 
 import (
+	"context"
 	"strconv"
+	"time"
 )
 
 // Information defines attributes of a video.
 type Information struct {
-	Width  int
-	Height int
-	FPS    float64
+	Width      int
+	Height     int
+	FPS        float64
+	Duration   time.Duration
+	FrameCount int
+	// VariableFrameRate is true if the frame rate of the video changes, in
+	//  which case FPS is only an average.
+	VariableFrameRate bool
+	// StreamIndex is the index within the file of the video stream which
+	//  was probed, or -1 if unknown.
+	StreamIndex int
+	// SampleAspectRatio is the width:height ratio of a single pixel, which
+	//  is not 1.0 for anamorphic video. 0.0 means unknown.
+	SampleAspectRatio float64
+	// DisplayAspectRatio is the width:height ratio of the video as it should
+	//  be displayed. 0.0 means unknown.
+	DisplayAspectRatio float64
 }
 
 // GetBasicInformation  extracts some basic attributes form the video pointed
 //  to by videoPath
 func GetBasicInformation(videoPath string) (Information, error) {
-	stringResults, err := QueryWithMediaInfo(videoPath, []string{"Width", "Height", "FrameRate"})
+	return GetBasicInformationContext(context.Background(), videoPath)
+}
+
+// GetBasicInformationContext is like GetBasicInformation, but mediainfo is
+//  killed if ctx is done before it finishes.
+func GetBasicInformationContext(ctx context.Context, videoPath string) (Information, error) {
+	stringResults, err := QueryWithMediaInfoContext(ctx, videoPath, []string{"Width", "Height", "FrameRate"})
 	if err != nil {
 		return Information{}, err
 	}
@@ -0,0 +1,190 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FFProbeProber uses ffprobe to extract the attributes of a video.
+type FFProbeProber struct{}
+
+type ffprobeOutput struct {
+	Streams []ffprobeStream `json:"streams"`
+	Format  ffprobeFormat   `json:"format"`
+}
+
+type ffprobeStream struct {
+	Index              int               `json:"index"`
+	CodecType          string            `json:"codec_type"`
+	Disposition        map[string]int    `json:"disposition"`
+	Width              int               `json:"width"`
+	Height             int               `json:"height"`
+	SampleAspectRatio  string            `json:"sample_aspect_ratio"`
+	DisplayAspectRatio string            `json:"display_aspect_ratio"`
+	RFrameRate         string            `json:"r_frame_rate"`
+	AvgFrameRate       string            `json:"avg_frame_rate"`
+	NbFrames           string            `json:"nb_frames"`
+	Duration           string            `json:"duration"`
+	Tags               map[string]string `json:"tags"`
+}
+
+type ffprobeFormat struct {
+	Duration string `json:"duration"`
+}
+
+// Probe extracts the attributes of the first video stream of the video
+//  pointed to by videoPath, skipping attached pictures (e.g. cover art).
+func (fp FFProbeProber) Probe(ctx context.Context, videoPath string) (Information, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-select_streams", "v",
+		"-show_streams",
+		"-show_format",
+		"-print_format", "json",
+		videoPath)
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return Information{}, ctx.Err()
+	}
+	if err != nil {
+		return Information{}, fmt.Errorf("ffprobe failed: %v", err)
+	}
+	return ParseFFProbeJSON(output)
+}
+
+// ParseFFProbeJSON maps the JSON output of ffprobe (with -show_streams and
+//  -show_format) to the Information of the first video stream which is not
+//  an attached picture.
+func ParseFFProbeJSON(data []byte) (Information, error) {
+	var output ffprobeOutput
+	err := json.Unmarshal(data, &output)
+	if err != nil {
+		return Information{}, fmt.Errorf("could not parse ffprobe output: %v", err)
+	}
+	stream, err := firstVideoStream(output.Streams)
+	if err != nil {
+		return Information{}, err
+	}
+
+	// Prefer the average frame rate, since the "real" frame rate is just the
+	//  lowest rate that can represent all timestamps.
+	realFPS, realErr := parseRational(stream.RFrameRate)
+	avgFPS, avgErr := parseRational(stream.AvgFrameRate)
+	fps := avgFPS
+	if avgErr != nil {
+		if realErr != nil {
+			return Information{}, fmt.Errorf("ffprobe did not provide a frame rate: %v", avgErr)
+		}
+		fps = realFPS
+	}
+
+	duration, err := parseDuration(stream, output.Format)
+	if err != nil {
+		return Information{}, err
+	}
+
+	return Information{
+		Width:             stream.Width,
+		Height:            stream.Height,
+		FPS:               fps,
+		Duration:          duration,
+		FrameCount:        frameCount(stream, duration, fps),
+		VariableFrameRate: realErr == nil && avgErr == nil && realFPS != avgFPS,
+		StreamIndex:       stream.Index,
+		// Not all videos declare their aspect ratios, so these are optional.
+		SampleAspectRatio:  parseRatio(stream.SampleAspectRatio),
+		DisplayAspectRatio: parseRatio(stream.DisplayAspectRatio),
+	}, nil
+}
+
+func firstVideoStream(streams []ffprobeStream) (ffprobeStream, error) {
+	for _, stream := range streams {
+		if stream.CodecType == "video" && stream.Disposition["attached_pic"] == 0 {
+			return stream, nil
+		}
+	}
+	return ffprobeStream{}, fmt.Errorf("ffprobe found no video stream")
+}
+
+func parseDuration(stream ffprobeStream, format ffprobeFormat) (time.Duration, error) {
+	for _, candidate := range []string{stream.Duration, format.Duration} {
+		if candidate == "" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(candidate, 64)
+		if err != nil {
+			return 0, fmt.Errorf("could not parse ffprobe duration %s: %v", candidate, err)
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	// Matroska keeps the stream duration in the tags.
+	if tag, ok := stream.Tags["DURATION"]; ok {
+		return parseTagDuration(tag)
+	}
+	return 0, nil
+}
+
+// e.g. 00:00:13.200000000
+func parseTagDuration(tag string) (time.Duration, error) {
+	parts := strings.Split(tag, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("could not parse ffprobe duration tag %s", tag)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("could not parse ffprobe duration tag %s: %v", tag, err)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("could not parse ffprobe duration tag %s: %v", tag, err)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse ffprobe duration tag %s: %v", tag, err)
+	}
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second)), nil
+}
+
+func frameCount(stream ffprobeStream, duration time.Duration, fps float64) int {
+	for _, candidate := range []string{stream.NbFrames, stream.Tags["NUMBER_OF_FRAMES"]} {
+		if count, err := strconv.Atoi(candidate); err == nil {
+			return count
+		}
+	}
+	// Estimate
+	return int(math.Round(duration.Seconds() * fps))
+}
+
+// e.g. 30000/1001
+func parseRational(rational string) (float64, error) {
+	parts := strings.Split(rational, "/")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid rational: %s", rational)
+	}
+	num, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rational: %s", rational)
+	}
+	den, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || den == 0 || num == 0 {
+		return 0, fmt.Errorf("invalid rational: %s", rational)
+	}
+	return num / den, nil
+}
+
+// e.g. 16:9. 0.0 is returned if the ratio is not valid, e.g. "N/A" or "0:1".
+func parseRatio(ratio string) float64 {
+	value, err := parseRational(strings.Replace(ratio, ":", "/", 1))
+	if err != nil {
+		return 0.0
+	}
+	return value
+}
@@ -1,6 +1,7 @@
 package video
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,10 +14,15 @@ const extractedFramePrefix = "extracted_frame"
 
 // ExtractFrames uses FFmpeg to extract a video into frames, and returns
 // An array of ordered filepaths to the resulting PNG files.
+//  outputDirectory is owned by the caller, and should not be shared with
+//  other concurrent extractions.
 func ExtractFrames(videoPath string, outputDirectory string) ([]string, error) {
-	// Delete direcory, if it exists
-	os.RemoveAll(outputDirectory)
+	return ExtractFramesContext(context.Background(), videoPath, outputDirectory)
+}
 
+// ExtractFramesContext is like ExtractFrames, but FFmpeg is killed if ctx is
+//  done before it finishes.
+func ExtractFramesContext(ctx context.Context, videoPath string, outputDirectory string) ([]string, error) {
 	// Create directory path
 	err := os.MkdirAll(outputDirectory, 0700)
 	if err != nil {
@@ -24,12 +30,18 @@ func ExtractFrames(videoPath string, outputDirectory string) ([]string, error) {
 	}
 
 	// Extract the frames
-	cmd := exec.Command("ffmpeg", "-i", videoPath, "-vf", "scale=64:48", path.Join(outputDirectory, extractedFramePrefix+"%06d.png"))
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-i", videoPath, "-vf", "scale=64:48", path.Join(outputDirectory, extractedFramePrefix+"%06d.png"))
 	err = cmd.Start()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to start ffmpeg: %v", err)
 	}
 	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to wait on ffmpeg: %v", err)
 	}
@@ -1,6 +1,7 @@
 package video
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -11,13 +12,22 @@ const separator = "-SEPARATOR-"
 // QueryWithMediaInfo queries the desired aspects of a video using
 //  mediainfo. The corresponding results for each parameter are returned.
 func QueryWithMediaInfo(videoPath string, videoParameters []string) ([]string, error) {
+	return QueryWithMediaInfoContext(context.Background(), videoPath, videoParameters)
+}
+
+// QueryWithMediaInfoContext is like QueryWithMediaInfo, but mediainfo is
+//  killed if ctx is done before it finishes.
+func QueryWithMediaInfoContext(ctx context.Context, videoPath string, videoParameters []string) ([]string, error) {
 	videoParameters = filterOutEmptyAndTrimWhitespace(videoParameters)
 	if len(videoParameters) == 0 {
 		return []string{}, nil
 	}
 	finalArgs := outputArg(videoParameters)
-	cmd := exec.Command("mediainfo", finalArgs, videoPath)
+	cmd := exec.CommandContext(ctx, "mediainfo", finalArgs, videoPath)
 	bytes, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,51 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Prober extracts the attributes of a video. Any external tool used is
+//  killed if ctx is done before it finishes.
+type Prober interface {
+	Probe(ctx context.Context, videoPath string) (Information, error)
+}
+
+// NewProber returns the Prober for the named backend, which may be
+//  "ffprobe" or "mediainfo".
+func NewProber(backend string) (Prober, error) {
+	switch backend {
+	case "ffprobe":
+		return FFProbeProber{}, nil
+	case "mediainfo":
+		return MediaInfoProber{}, nil
+	}
+	return nil, fmt.Errorf("unknown probe backend: %s", backend)
+}
+
+// MediaInfoProber uses mediainfo to extract the attributes of a video. Only
+//  the Width, Height, FPS and aspect ratios are provided, and the
+//  StreamIndex is unknown.
+type MediaInfoProber struct{}
+
+// Probe extracts the attributes of the video pointed to by videoPath.
+func (mp MediaInfoProber) Probe(ctx context.Context, videoPath string) (Information, error) {
+	info, err := GetBasicInformationContext(ctx, videoPath)
+	if err != nil {
+		return Information{}, err
+	}
+	info.StreamIndex = -1
+
+	// Not all videos declare their aspect ratios, so these are optional.
+	stringResults, err := QueryWithMediaInfoContext(ctx, videoPath, []string{"PixelAspectRatio", "DisplayAspectRatio"})
+	if ctx.Err() != nil {
+		return Information{}, ctx.Err()
+	}
+	if err != nil {
+		return info, nil
+	}
+	info.SampleAspectRatio, _ = strconv.ParseFloat(stringResults[0], 64)
+	info.DisplayAspectRatio, _ = strconv.ParseFloat(stringResults[1], 64)
+	return info, nil
+}
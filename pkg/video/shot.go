@@ -0,0 +1,194 @@
+package video
+
+import (
+	"image"
+	"image/color"
+	"sort"
+
+	cabiriaImage "github.com/liampulles/cabiria/pkg/image"
+)
+
+// CutThreshold is the minimum difference (see image.Diff) between two
+//  consecutive frames for there to be a cut between them.
+const CutThreshold = 0.15
+
+// cutPeakRatio is how many times larger the difference at a cut must be than
+//  the mean difference around it, so that fast motion is not taken for cuts.
+const cutPeakRatio = 3.0
+
+// cutWindow is how many differences either side of a cut are compared with it.
+const cutWindow = 2
+
+// BlackThreshold is the maximum mean lightness (from 0 to 1) of a black
+//  frame, which a fade starts from or ends at.
+const BlackThreshold = 0.06
+
+// MinFadeLength is the fewest frames over which lightness must steadily
+//  change from or to black to be a fade.
+const MinFadeLength = 3
+
+// fadeStep is the least change in mean lightness between consecutive frames
+//  of a fade.
+const fadeStep = 0.005
+
+// Fade is a run of frames which fade in from, or out to, black. Start is the
+//  first and End the last frame which is neither black nor fully visible.
+type Fade struct {
+	Start int
+	End   int
+}
+
+// ShotBoundaries are the cuts and fades found in a video. Cuts are the first
+//  frame of each new shot.
+type ShotBoundaries struct {
+	Cuts     []int
+	FadeIns  []Fade
+	FadeOuts []Fade
+}
+
+// Starts gives the frames at which something (e.g. an intertitle) may start
+//  to be visible: the first frame after a cut, or of a fade in. They are
+//  sorted.
+func (sb ShotBoundaries) Starts() []int {
+	result := append([]int{}, sb.Cuts...)
+	for _, elem := range sb.FadeIns {
+		result = append(result, elem.Start)
+	}
+	return sortedUnique(result)
+}
+
+// Ends gives the frames at which something may stop being visible: the last
+//  frame before a cut, or of a fade out. They are sorted.
+func (sb ShotBoundaries) Ends() []int {
+	var result []int
+	for _, elem := range sb.Cuts {
+		if elem > 0 {
+			result = append(result, elem-1)
+		}
+	}
+	for _, elem := range sb.FadeOuts {
+		result = append(result, elem.End)
+	}
+	return sortedUnique(result)
+}
+
+// DetectShotBoundaries finds cuts and fades, given the difference (see
+//  image.Diff) of each frame from its predecessor and the mean lightness of
+//  each frame. The difference of the first frame is ignored.
+func DetectShotBoundaries(diffs []float64, lightness []float64) ShotBoundaries {
+	return ShotBoundaries{
+		Cuts:     detectCuts(diffs),
+		FadeIns:  detectFadeIns(lightness),
+		FadeOuts: detectFadeOuts(lightness),
+	}
+}
+
+func detectCuts(diffs []float64) []int {
+	var result []int
+	for i := 1; i < len(diffs); i++ {
+		if diffs[i] < CutThreshold {
+			continue
+		}
+		sum := 0.0
+		count := 0
+		for j := i - cutWindow; j <= i+cutWindow; j++ {
+			if j >= 1 && j < len(diffs) && j != i {
+				sum += diffs[j]
+				count++
+			}
+		}
+		if count == 0 || diffs[i] > cutPeakRatio*sum/float64(count) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func detectFadeIns(lightness []float64) []Fade {
+	var result []Fade
+	for i := 0; i+1 < len(lightness); i++ {
+		if lightness[i] > BlackThreshold || lightness[i+1] <= BlackThreshold {
+			continue
+		}
+		end := i + 1
+		for end+1 < len(lightness) && lightness[end+1] >= lightness[end]+fadeStep {
+			end++
+		}
+		if end-i >= MinFadeLength {
+			result = append(result, Fade{Start: i + 1, End: end - 1})
+		}
+	}
+	return result
+}
+
+func detectFadeOuts(lightness []float64) []Fade {
+	var result []Fade
+	for i := len(lightness) - 1; i > 0; i-- {
+		if lightness[i] > BlackThreshold || lightness[i-1] <= BlackThreshold {
+			continue
+		}
+		start := i - 1
+		for start > 0 && lightness[start-1] >= lightness[start]+fadeStep {
+			start--
+		}
+		if i-start >= MinFadeLength {
+			result = append([]Fade{{Start: start + 1, End: i - 1}}, result...)
+		}
+	}
+	return result
+}
+
+// ShotDetector finds cuts and fades, given every frame of a video in order.
+type ShotDetector struct {
+	last      image.Image
+	diffs     []float64
+	lightness []float64
+}
+
+// Add compares frame with the previously added frame, and records it.
+func (sd *ShotDetector) Add(frame image.Image) error {
+	diff := 0.0
+	if sd.last != nil {
+		var err error
+		diff, err = cabiriaImage.Diff(sd.last, frame)
+		if err != nil {
+			return err
+		}
+	}
+	sd.AddDiff(frame, diff)
+	sd.last = frame
+	return nil
+}
+
+// AddDiff is like Add, but is given the difference (see image.Diff) between
+//  the frame and the previous frame, for when it has already been computed.
+//  The difference given for the first frame is ignored.
+func (sd *ShotDetector) AddDiff(frame image.Image, diff float64) {
+	sd.diffs = append(sd.diffs, diff)
+	sd.lightness = append(sd.lightness, meanLightness(frame))
+}
+
+// Boundaries detects the cuts and fades of the frames added so far.
+func (sd *ShotDetector) Boundaries() ShotBoundaries {
+	return DetectShotBoundaries(sd.diffs, sd.lightness)
+}
+
+// meanLightness is the mean gray level of a frame, from 0 to 1.
+func meanLightness(frame image.Image) float64 {
+	total := 0.0
+	cabiriaImage.ForEachPixel(frame, func(x, y int, col color.Color) {
+		total += float64(color.Gray16Model.Convert(col).(color.Gray16).Y) / 65535.0
+	})
+	return total / float64(frame.Bounds().Dx()*frame.Bounds().Dy())
+}
+
+func sortedUnique(values []int) []int {
+	sort.Ints(values)
+	var result []int
+	for i, elem := range values {
+		if i == 0 || elem != values[i-1] {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
@@ -0,0 +1,257 @@
+package video
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameHandler is called for each frame streamed from a video, in order.
+type FrameHandler func(index int, frame image.Image) error
+
+// Progress is how far FFmpeg has got through a video, as reported by its
+//  -progress option.
+type Progress struct {
+	// Frame is the number of frames decoded so far.
+	Frame int
+	// OutTime is the timestamp reached so far.
+	OutTime time.Duration
+	// Done is true once FFmpeg has finished.
+	Done bool
+}
+
+// ProgressHandler is called each time FFmpeg reports its progress.
+type ProgressHandler func(progress Progress)
+
+const stderrTailLength = 10
+
+var showInfoPTSTime = regexp.MustCompile(`^\[Parsed_showinfo_\d+ @ [^\]]*\].*\bpts_time:\s*(\S+)`)
+
+var progressKeyValue = regexp.MustCompile(`^([a-z0-9_]+)=(.*)$`)
+
+// ErrIncompleteTimestamps is returned (wrapped) by StreamFramesContext when
+//  FFmpeg did not report the timestamp of every frame. Every frame was still
+//  handled, so the caller may carry on without timestamps.
+var ErrIncompleteTimestamps = errors.New("ffmpeg did not report the timestamp of every frame")
+
+// StreamFrames uses FFmpeg to decode a video into raw RGB frames scaled to
+//  width x height, and passes each to handler as it is read. No frames are
+//  written to disk. The presentation timestamp of each frame, relative to the
+//  first frame, is returned - or ErrIncompleteTimestamps if FFmpeg did not
+//  report them all.
+func StreamFrames(videoPath string, width, height int, handler FrameHandler) ([]time.Duration, error) {
+	return StreamFramesContext(context.Background(), videoPath, -1, width, height, handler, nil)
+}
+
+// StreamFramesContext is like StreamFrames, but decodes the stream with the
+//  given index within the file (see Information.StreamIndex), or FFmpeg's
+//  choice of video stream if it is negative. FFmpeg is killed if ctx is done
+//  before it finishes. If progress is not nil, it is called with FFmpeg's
+//  progress as it goes.
+func StreamFramesContext(ctx context.Context, videoPath string, stream int, width, height int, handler FrameHandler, progress ProgressHandler) ([]time.Duration, error) {
+	args := []string{
+		"-hide_banner",
+		"-nostats",
+		"-loglevel", "info",
+	}
+	if progress != nil {
+		args = append(args, "-progress", "pipe:2")
+	}
+	args = append(args, "-i", videoPath)
+	if stream >= 0 {
+		args = append(args, "-map", fmt.Sprintf("0:%d", stream))
+	}
+	args = append(args,
+		"-vf", fmt.Sprintf("scale=%d:%d,showinfo", width, height),
+		// Don't duplicate or drop frames for variable frame rate video.
+		"-vsync", "passthrough",
+		"-f", "rawvideo",
+		"-pix_fmt", "rgb24",
+		"-")
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Start()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %v", err)
+	}
+
+	// FFmpeg reports the timestamps, progress and any errors on stderr
+	var timestamps []time.Duration
+	var tail []string
+	stderrDone := make(chan struct{})
+	go func() {
+		timestamps, tail = readStderr(stderr, progress)
+		close(stderrDone)
+	}()
+
+	frameCount := 0
+	err = ReadRGBFrames(stdout, width, height, func(index int, frame image.Image) error {
+		frameCount++
+		return handler(index, frame)
+	})
+	if err != nil {
+		cmd.Process.Kill()
+		<-stderrDone
+		cmd.Wait()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	<-stderrDone
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait on ffmpeg: %v: %s", err, strings.Join(tail, "\n"))
+	}
+	if frameCount == 0 {
+		return nil, nil
+	}
+	if len(timestamps) != frameCount {
+		return nil, fmt.Errorf("%w: found %d for %d frames", ErrIncompleteTimestamps, len(timestamps), frameCount)
+	}
+	return relativeTo(timestamps, timestamps[0]), nil
+}
+
+// ParseShowInfoTimestamp extracts the presentation timestamp from a line
+//  logged by FFmpeg's showinfo filter. If the line is not a showinfo frame
+//  line, false is returned.
+func ParseShowInfoTimestamp(line string) (time.Duration, bool) {
+	match := showInfoPTSTime.FindStringSubmatch(line)
+	if match == nil {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(math.Round(seconds * float64(time.Second))), true
+}
+
+// ParseProgressLine splits a key=value line written by FFmpeg's -progress
+//  option. If the line is not of that form, false is returned.
+func ParseProgressLine(line string) (key, value string, ok bool) {
+	match := progressKeyValue.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// Apply updates p with a key value pair written by FFmpeg's -progress option.
+//  Unknown keys are ignored. True is returned if the pair ends a block of
+//  progress, i.e. p is ready to be reported.
+func (p *Progress) Apply(key, value string) bool {
+	switch key {
+	case "frame":
+		if frame, err := strconv.Atoi(value); err == nil {
+			p.Frame = frame
+		}
+	// Despite the name, out_time_ms is in microseconds too.
+	case "out_time_us", "out_time_ms":
+		if us, err := strconv.ParseInt(value, 10, 64); err == nil {
+			p.OutTime = time.Duration(us) * time.Microsecond
+		}
+	case "progress":
+		p.Done = value == "end"
+		return true
+	}
+	return false
+}
+
+// ReadRGBFrames reads consecutive packed 24-bit RGB frames of width x height
+//  from r, and passes each to handler until r is exhausted.
+func ReadRGBFrames(r io.Reader, width, height int, handler FrameHandler) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("frame dimensions must be positive. Width: %d, height: %d", width, height)
+	}
+	buf := make([]byte, width*height*3)
+	for index := 0; ; index++ {
+		_, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			return nil
+		}
+		if err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("frame %d is incomplete", index)
+		}
+		if err != nil {
+			return err
+		}
+		err = handler(index, rgbAsImage(buf, width, height))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func readStderr(stderr io.Reader, progress ProgressHandler) ([]time.Duration, []string) {
+	var timestamps []time.Duration
+	var tail []string
+	var current Progress
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if timestamp, ok := ParseShowInfoTimestamp(line); ok {
+			timestamps = append(timestamps, timestamp)
+			continue
+		}
+		if key, value, ok := ParseProgressLine(line); ok && progress != nil {
+			if current.Apply(key, value) {
+				progress(current)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "[Parsed_showinfo") {
+			continue
+		}
+		tail = append(tail, line)
+		if len(tail) > stderrTailLength {
+			tail = tail[1:]
+		}
+	}
+	// Keep draining, so that FFmpeg is not blocked.
+	io.Copy(ioutil.Discard, stderr)
+	return timestamps, tail
+}
+
+func relativeTo(timestamps []time.Duration, origin time.Duration) []time.Duration {
+	result := make([]time.Duration, len(timestamps))
+	for i, elem := range timestamps {
+		result[i] = elem - origin
+	}
+	return result
+}
+
+func rgbAsImage(rgb []byte, width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i, j := 0, 0; i < len(rgb); i, j = i+3, j+4 {
+		img.Pix[j] = rgb[i]
+		img.Pix[j+1] = rgb[i+1]
+		img.Pix[j+2] = rgb[i+2]
+		img.Pix[j+3] = 255
+	}
+	return img
+}
@@ -0,0 +1,126 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/liampulles/cabiria/cmd/cabiria-generate/core"
+	"github.com/liampulles/cabiria/cmd/cabiria-generate/input"
+	"github.com/liampulles/cabiria/pkg/log"
+)
+
+func TestRunBatch(t *testing.T) {
+	// Setup fixture
+	dir := path.Join(os.TempDir(), "cabiria", "runBatchTest")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("Could not clear fixture: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Could not create fixture: %v", err)
+	}
+	for _, elem := range []string{"a.mkv", "a.srt", "b.mkv", "b.srt", "c.mkv", "c.srt"} {
+		if err := ioutil.WriteFile(path.Join(dir, elem), nil, 0644); err != nil {
+			t.Fatalf("Could not create fixture: %v", err)
+		}
+	}
+	// The flags can only be defined once
+	config, err := input.GetGenerateConfiguration([]string{"cabiria-generate", "-batch", dir, "-jobs", "2"})
+	if err != nil {
+		t.Fatalf("Could not get configuration: %v", err)
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	var tests = []struct {
+		name              string
+		ctx               context.Context
+		failing           string
+		expectedErr       bool
+		expectedProcessed []string
+		expectedLog       []string
+	}{
+		{
+			"All succeed",
+			context.Background(),
+			"",
+			false,
+			[]string{"a.mkv", "b.mkv", "c.mkv"},
+			[]string{
+				`level=info msg="Processed film" film=a.mkv`,
+				`level=info msg="Processed film" film=b.mkv`,
+				`level=info msg="Processed film" film=c.mkv`,
+				`level=info msg="Processed batch" films=3 failed=0`,
+			},
+		},
+		{
+			"One fails, but the others are still processed",
+			context.Background(),
+			"b.mkv",
+			true,
+			[]string{"a.mkv", "b.mkv", "c.mkv"},
+			[]string{
+				`level=info msg="Processed film" film=a.mkv`,
+				`level=error msg="Could not process film" film=b.mkv`,
+				`error="no intertitles"`,
+				`level=info msg="Processed film" film=c.mkv`,
+				`level=warn msg="Processed batch" films=3 failed=1 failed_films=b.mkv`,
+			},
+		},
+		{
+			"Interrupted, so none are started",
+			cancelled,
+			"",
+			true,
+			nil,
+			[]string{
+				`level=error msg="Could not process film" film=a.mkv`,
+				`level=warn msg="Processed batch" films=3 failed=3`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup fixture
+			var out bytes.Buffer
+			core.SetLogger(log.New(&out, log.Info))
+			var mutex sync.Mutex
+			var processed []string
+			process := func(ctx context.Context, config *input.GenerateConfiguration) error {
+				name := path.Base(config.VideoPath())
+				mutex.Lock()
+				processed = append(processed, name)
+				mutex.Unlock()
+				if name == test.failing {
+					return errors.New("no intertitles")
+				}
+				return nil
+			}
+
+			// Exercise SUT
+			err := core.RunBatch(test.ctx, &config, process)
+
+			// Verify result
+			if (err != nil) != test.expectedErr {
+				t.Errorf("Error differs. Actual: %v, Expected an error: %v", err, test.expectedErr)
+			}
+			sort.Strings(processed)
+			if !reflect.DeepEqual(processed, test.expectedProcessed) {
+				t.Errorf("Processed films differ. Actual: %v, Expected: %v", processed, test.expectedProcessed)
+			}
+			for _, elem := range test.expectedLog {
+				if !strings.Contains(out.String(), elem) {
+					t.Errorf("Expected the log to contain %q, but it is:\n%s", elem, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package input_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/liampulles/cabiria/cmd/cabiria-generate/input"
+)
+
+func TestFindFilms_WhenValid(t *testing.T) {
+	// Setup fixture
+	dir := makeDir(t, "findFilmsTest",
+		"Nosferatu.mkv", "Nosferatu.srt",
+		"Metropolis.MP4", "Metropolis.vtt", "Metropolis.cabiria.ass",
+		"Extra.mkv", "notes.txt", "frames/Nosferatu.sub")
+	expected := []input.Film{
+		{
+			VideoPath:     path.Join(dir, "Metropolis.MP4"),
+			SubtitlesPath: path.Join(dir, "Metropolis.vtt"),
+			ASSPath:       path.Join(dir, "Metropolis.cabiria.ass"),
+		},
+		{
+			VideoPath:     path.Join(dir, "Nosferatu.mkv"),
+			SubtitlesPath: path.Join(dir, "Nosferatu.srt"),
+			ASSPath:       path.Join(dir, "Nosferatu.cabiria.ass"),
+		},
+	}
+
+	// Exercise SUT
+	actual, err := input.FindFilms(dir)
+
+	// Verify result
+	if err != nil {
+		t.Fatalf("SUT returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, expected)
+	}
+}
+
+func TestFindFilms_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = [][]string{
+		// Subtitles without a video
+		{"Nosferatu.mkv", "Nosferatu.srt", "Metropolis.srt"},
+		// Several subtitles or videos for a film
+		{"Nosferatu.mkv", "Nosferatu.srt", "Nosferatu.ass"},
+		{"Nosferatu.mkv", "Nosferatu.mp4", "Nosferatu.srt"},
+		// No pairs
+		{"Nosferatu.mkv", "notes.txt"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			dir := makeDir(t, "findFilmsTest", test...)
+
+			// Exercise SUT
+			_, err := input.FindFilms(dir)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to return an error")
+			}
+		})
+	}
+}
+
+func TestFindFilms_WhenDirectoryDoesNotExist(t *testing.T) {
+	// Exercise SUT
+	_, err := input.FindFilms("this/path/does/not.exist")
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+func TestReadManifest_WhenValid(t *testing.T) {
+	// Setup fixture
+	dir := makeDir(t, "manifestTest")
+	manifest := writeManifest(t, dir, `
+- video: Nosferatu.mkv
+  subs: subs/Nosferatu.en.srt
+  ass: /films/Nosferatu.en.ass
+- video: /films/Metropolis.mkv
+  srt: Metropolis.srt
+`)
+	expected := []input.Film{
+		{
+			VideoPath:     path.Join(dir, "Nosferatu.mkv"),
+			SubtitlesPath: path.Join(dir, "subs", "Nosferatu.en.srt"),
+			ASSPath:       "/films/Nosferatu.en.ass",
+		},
+		{
+			VideoPath:     "/films/Metropolis.mkv",
+			SubtitlesPath: path.Join(dir, "Metropolis.srt"),
+			ASSPath:       path.Join(dir, "Metropolis.cabiria.ass"),
+		},
+	}
+
+	// Exercise SUT
+	actual, err := input.ReadManifest(manifest)
+
+	// Verify result
+	if err != nil {
+		t.Fatalf("SUT returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, expected)
+	}
+}
+
+func TestReadManifest_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []string{
+		// Not YAML, or not a list
+		`[{video: Nosferatu.mkv`,
+		`video: Nosferatu.mkv`,
+		// Unknown field
+		`[{video: Nosferatu.mkv, subs: Nosferatu.srt, out: Nosferatu.ass}]`,
+		// No films
+		`[]`,
+		// Without a video or subs
+		`[{subs: Nosferatu.srt}]`,
+		`[{video: Nosferatu.mkv}]`,
+		// Subs given twice
+		`[{video: Nosferatu.mkv, subs: Nosferatu.srt, srt: Nosferatu.en.srt}]`,
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			manifest := writeManifest(t, makeDir(t, "manifestTest"), test)
+
+			// Exercise SUT
+			_, err := input.ReadManifest(manifest)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to return an error")
+			}
+		})
+	}
+}
+
+func TestReadManifest_WhenFileDoesNotExist(t *testing.T) {
+	// Exercise SUT
+	_, err := input.ReadManifest("this/path/does/not.exist")
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+// makeDir creates an empty directory called name in the temporary directory,
+//  with an empty file at each of files.
+func makeDir(t *testing.T, name string, files ...string) string {
+	dir := path.Join(os.TempDir(), "cabiria", name)
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("Could not clear fixture: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Could not create fixture: %v", err)
+	}
+	for _, elem := range files {
+		file := path.Join(dir, elem)
+		if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+			t.Fatalf("Could not create fixture: %v", err)
+		}
+		if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+			t.Fatalf("Could not create fixture: %v", err)
+		}
+	}
+	return dir
+}
+
+func writeManifest(t *testing.T, dir string, text string) string {
+	manifest := path.Join(dir, "manifest.yaml")
+	if err := ioutil.WriteFile(manifest, []byte(text), 0644); err != nil {
+		t.Fatalf("Could not write fixture: %v", err)
+	}
+	return manifest
+}
@@ -0,0 +1,68 @@
+package array_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/array"
+)
+
+func TestHysteresisThreshold(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		confidences []float64
+		low         float64
+		high        float64
+		expected    []bool
+	}{
+		// Empty
+		{
+			[]float64{},
+			0.3, 0.7,
+			bools(),
+		},
+		// Same thresholds -> Like a single threshold
+		{
+			[]float64{0.2, 0.5, 0.6, 1.0, 0.0},
+			0.5, 0.5,
+			bools(0, 0, 1, 1, 0),
+		},
+		// Weak run without a strong item -> Off
+		{
+			[]float64{0.2, 0.5, 0.6, 0.5, 0.2},
+			0.3, 0.7,
+			bools(0, 0, 0, 0, 0),
+		},
+		// Weak items next to a strong item -> On
+		{
+			[]float64{0.2, 0.5, 0.8, 0.4, 0.2, 0.5},
+			0.3, 0.7,
+			bools(0, 1, 1, 1, 0, 0),
+		},
+		// Runs at the start and end
+		{
+			[]float64{0.8, 0.4, 0.2, 0.4, 0.9},
+			0.3, 0.7,
+			bools(1, 1, 0, 1, 1),
+		},
+		// Dip below low -> Separate runs
+		{
+			[]float64{0.8, 0.4, 0.3, 0.4, 0.5},
+			0.3, 0.7,
+			bools(1, 1, 0, 0, 0),
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := array.HysteresisThreshold(test.confidences, test.low, test.high)
+
+			// Verify result
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
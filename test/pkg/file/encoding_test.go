@@ -0,0 +1,251 @@
+package file_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/file"
+)
+
+// "Привет" in Windows-1251
+var cyrillic = []byte{0xCF, 0xF0, 0xE8, 0xE2, 0xE5, 0xF2}
+
+// "Città è bella" in ISO-8859-1
+var latin = []byte{'C', 'i', 't', 't', 0xE0, ' ', 0xE8, ' ', 'b', 'e', 'l', 'l', 'a'}
+
+func TestDetectEncoding(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		data     []byte
+		expected string
+	}{
+		{
+			[]byte("Plain ASCII"),
+			file.UTF8,
+		},
+		{
+			[]byte("Città è bella"),
+			file.UTF8,
+		},
+		{
+			append([]byte{0xEF, 0xBB, 0xBF}, "BOM"...),
+			file.UTF8,
+		},
+		{
+			[]byte{0xFF, 0xFE, 'H', 0, 'i', 0},
+			file.UTF16LE,
+		},
+		{
+			[]byte{0xFE, 0xFF, 0, 'H', 0, 'i'},
+			file.UTF16BE,
+		},
+		// No BOM
+		{
+			[]byte{'H', 0, 'e', 0, 'l', 0, 'l', 0, 'o', 0},
+			file.UTF16LE,
+		},
+		{
+			[]byte{0, 'H', 0, 'e', 0, 'l', 0, 'l', 0, 'o'},
+			file.UTF16BE,
+		},
+		{
+			latin,
+			file.Windows1252,
+		},
+		{
+			cyrillic,
+			file.Windows1251,
+		},
+		// More accented than ASCII letters: "À côté: été, déjà, où ça?"
+		{
+			[]byte("\xC0 c\xF4t\xE9: \xE9t\xE9, d\xE9j\xE0, o\xF9 \xE7a?"),
+			file.Windows1252,
+		},
+		// Cyrillic among ASCII words: "Dialogue: 0,Default,Привет, как дела? Всё хорошо."
+		{
+			[]byte("Dialogue: 0,Default,\xCF\xF0\xE8\xE2\xE5\xF2, \xEA\xE0\xEA \xE4\xE5\xEB\xE0? \xC2\xF1\xB8 \xF5\xEE\xF0\xEE\xF8\xEE."),
+			file.Windows1251,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := file.DetectEncoding(test.data)
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %s, Expected: %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestDecode_WhenValid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		data     []byte
+		encoding string
+		expected string
+	}{
+		{
+			append([]byte{0xEF, 0xBB, 0xBF}, "Città"...),
+			file.UTF8,
+			"Città",
+		},
+		{
+			[]byte{0xFF, 0xFE, 'C', 0, 0xE0, 0, 0x3D, 0xD8, 0x00, 0xDE},
+			file.UTF16LE,
+			"Cà😀",
+		},
+		{
+			[]byte{0, 'C', 0, 0xE0},
+			"UTF-16BE",
+			"Cà",
+		},
+		// UTF-16 takes its byte order from its byte order mark, or else is
+		//  big endian
+		{
+			[]byte{0xFF, 0xFE, 'C', 0, 0xE0, 0},
+			file.UTF16,
+			"Cà",
+		},
+		{
+			[]byte{0xFE, 0xFF, 0, 'C', 0, 0xE0},
+			file.UTF16,
+			"Cà",
+		},
+		{
+			[]byte{0, 'C', 0, 0xE0},
+			file.UTF16,
+			"Cà",
+		},
+		{
+			latin,
+			file.ISO88591,
+			"Città è bella",
+		},
+		{
+			[]byte{0x93, 'H', 'i', 0x94, ' ', 0x80},
+			"cp1252",
+			"“Hi” €",
+		},
+		{
+			cyrillic,
+			file.Windows1251,
+			"Привет",
+		},
+		{
+			[]byte{0xA8, 0xB8, 0xB9, 0xC0, 0xDF, 0xFF},
+			file.Windows1251,
+			"Ёё№АЯя",
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, err := file.Decode(test.data, test.encoding)
+
+			// Verify result
+			if err != nil {
+				t.Fatalf("SUT returned an error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %q, Expected: %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestDecode_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		data     []byte
+		encoding string
+	}{
+		{
+			cyrillic,
+			file.UTF8,
+		},
+		{
+			[]byte{'H', 0, 'i'},
+			file.UTF16LE,
+		},
+		{
+			[]byte("Hi"),
+			"ebcdic",
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			_, err := file.Decode(test.data, test.encoding)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to return an error")
+			}
+		})
+	}
+}
+
+func TestCanonicalEncoding(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		name     string
+		expected string
+	}{
+		{"utf-8", file.UTF8},
+		{"UTF8", file.UTF8},
+		{"utf-16", file.UTF16},
+		{"UTF16", file.UTF16},
+		{"Latin1", file.ISO88591},
+		{"CP1251", file.Windows1251},
+		{" windows-1252 ", file.Windows1252},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%s]", test.name), func(t *testing.T) {
+			// Exercise SUT
+			actual, err := file.CanonicalEncoding(test.name)
+
+			// Verify result
+			if err != nil {
+				t.Fatalf("SUT returned an error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %s, Expected: %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestReadLinesFromEncodedTextFile(t *testing.T) {
+	// Setup fixture
+	fixture := path.Join(os.TempDir(), "cabiria", "encodedTextFile.txt")
+	data := []byte{0xFF, 0xFE}
+	for _, elem := range "Первый\r\nВторой\r\n" {
+		data = append(data, byte(elem), byte(elem>>8))
+	}
+	if err := ioutil.WriteFile(fixture, data, 0644); err != nil {
+		t.Fatalf("Could not write fixture: %v", err)
+	}
+
+	// Exercise SUT
+	actual, err := file.ReadLinesFromEncodedTextFile(fixture, "")
+
+	// Verify result
+	if err != nil {
+		t.Fatalf("SUT returned an error: %v", err)
+	}
+	expected := []string{"Первый", "Второй"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %q, Expected: %q", actual, expected)
+	}
+}
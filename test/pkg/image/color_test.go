@@ -227,3 +227,79 @@ func colorFromRGB(r, g, b uint8) color.Color {
 func colorFromHSV(h, s, v float64) color.Color {
 	return colorful.Hsv(h, s, v)
 }
+
+func TestHexColor(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		col      color.Color
+		expected string
+	}{
+		{nil, ""},
+		{color.Black, "#000000"},
+		{color.White, "#ffffff"},
+		{color.RGBA{R: 255, G: 128, B: 1, A: 255}, "#ff8001"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := cabiriaImage.HexColor(test.col)
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %s, Expected: %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseHexColor_WhenValid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		hex      string
+		expected color.Color
+	}{
+		{"", nil},
+		{"#000000", color.RGBA{A: 255}},
+		{"#ff8001", color.RGBA{R: 255, G: 128, B: 1, A: 255}},
+		{"#FF8001", color.RGBA{R: 255, G: 128, B: 1, A: 255}},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, err := cabiriaImage.ParseHexColor(test.hex)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT returned an error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseHexColor_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []string{
+		"ff8001",
+		"#ff80",
+		"#ff800100",
+		"#gg8001",
+		"white",
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			_, err := cabiriaImage.ParseHexColor(test)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to throw an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package correct_test
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/intertitle/correct"
+)
+
+func TestLoad_WhenValid(t *testing.T) {
+	// Setup fixture
+	fixture := `
+- action: add
+  start: 62.5
+  end: 66
+- action: delete
+  at: 130.2
+- action: adjust
+  at: 200
+  end: 204.5
+- action: style
+  at: 200
+  foreground: "#f0e0c0"
+`
+	path := writeFixture(t, fixture)
+	expected := []correct.Correction{
+		{Action: correct.Add, Start: duration(62500 * time.Millisecond), End: duration(66 * time.Second)},
+		{Action: correct.Delete, At: 130200 * time.Millisecond},
+		{Action: correct.Adjust, At: 200 * time.Second, End: duration(204500 * time.Millisecond)},
+		{Action: correct.Restyle, At: 200 * time.Second, Foreground: color.RGBA{R: 240, G: 224, B: 192, A: 255}},
+	}
+
+	// Exercise SUT
+	actual, err := correct.Load(path)
+
+	// Verify result
+	if err != nil {
+		t.Fatalf("SUT returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, expected)
+	}
+}
+
+func TestLoad_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []string{
+		// Not a list
+		`action: delete`,
+		// Unknown field
+		`[{action: delete, at: 1, when: 2}]`,
+		// Unknown action
+		`[{action: move, at: 1}]`,
+		// Add without bounds, or with at
+		`[{action: add, start: 1}]`,
+		`[{action: add, at: 1, start: 1, end: 2}]`,
+		// Add which ends before it starts
+		`[{action: add, start: 2, end: 1}]`,
+		// Negative time
+		`[{action: add, start: -1, end: 1}]`,
+		// Delete, adjust or style without at
+		`[{action: delete}]`,
+		`[{action: adjust, start: 1}]`,
+		`[{action: style, foreground: "#ffffff"}]`,
+		// Adjust without bounds, or which moves away from at
+		`[{action: adjust, at: 1}]`,
+		`[{action: adjust, at: 1, start: 2}]`,
+		`[{action: adjust, at: 3, end: 2}]`,
+		// Style without colors, or with a bad color
+		`[{action: style, at: 1}]`,
+		`[{action: style, at: 1, background: black}]`,
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			path := writeFixture(t, test)
+
+			// Exercise SUT
+			_, err := correct.Load(path)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to throw an error")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	// Setup fixture
+	grey := color.RGBA{R: 25, G: 25, B: 25, A: 255}
+	sepia := color.RGBA{R: 240, G: 224, B: 192, A: 255}
+	var tests = []struct {
+		ranges          []intertitle.Range
+		corrections     []correct.Correction
+		expected        []intertitle.Range
+		expectedSkipped []int
+	}{
+		// No corrections
+		{
+			[]intertitle.Range{interRange(100, 150, color.White, grey)},
+			nil,
+			[]intertitle.Range{interRange(100, 150, color.White, grey)},
+			nil,
+		},
+		// Add, sorted by start
+		{
+			[]intertitle.Range{interRange(100, 150, color.White, grey)},
+			[]correct.Correction{
+				{Action: correct.Add, Start: duration(time.Second), End: duration(2 * time.Second)},
+			},
+			[]intertitle.Range{
+				timedRange(25, 50, time.Second, 2*time.Second, color.White, color.Black),
+				interRange(100, 150, color.White, grey),
+			},
+			nil,
+		},
+		// Add, replacing overlapped ranges
+		{
+			[]intertitle.Range{
+				interRange(100, 150, color.White, grey),
+				interRange(160, 170, color.White, grey),
+				interRange(300, 350, color.White, grey),
+			},
+			[]correct.Correction{
+				{Action: correct.Add, Start: duration(3 * time.Second), End: duration(8 * time.Second), Background: grey},
+			},
+			[]intertitle.Range{
+				timedRange(75, 200, 3*time.Second, 8*time.Second, color.White, grey),
+				interRange(300, 350, color.White, grey),
+			},
+			nil,
+		},
+		// Delete
+		{
+			[]intertitle.Range{
+				interRange(100, 150, color.White, grey),
+				interRange(300, 350, color.White, grey),
+			},
+			[]correct.Correction{
+				{Action: correct.Delete, At: 13 * time.Second},
+			},
+			[]intertitle.Range{interRange(100, 150, color.White, grey)},
+			nil,
+		},
+		// Adjust start, end or both
+		{
+			[]intertitle.Range{
+				interRange(100, 150, color.White, grey),
+				interRange(300, 350, color.White, grey),
+				interRange(400, 450, color.White, grey),
+			},
+			[]correct.Correction{
+				{Action: correct.Adjust, At: 5 * time.Second, Start: duration(3 * time.Second)},
+				{Action: correct.Adjust, At: 13 * time.Second, End: duration(15 * time.Second)},
+				{Action: correct.Adjust, At: 17 * time.Second, Start: duration(16 * time.Second), End: duration(17 * time.Second)},
+			},
+			[]intertitle.Range{
+				timedRange(75, 150, 3*time.Second, 6*time.Second, color.White, grey),
+				timedRange(300, 375, 12*time.Second, 15*time.Second, color.White, grey),
+				timedRange(400, 425, 16*time.Second, 17*time.Second, color.White, grey),
+			},
+			nil,
+		},
+		// Adjust into a neighbour, replacing it
+		{
+			[]intertitle.Range{
+				interRange(100, 150, color.White, grey),
+				interRange(160, 170, color.White, sepia),
+				interRange(300, 350, color.White, grey),
+			},
+			[]correct.Correction{
+				{Action: correct.Adjust, At: 5 * time.Second, End: duration(7 * time.Second)},
+			},
+			[]intertitle.Range{
+				timedRange(100, 175, 4*time.Second, 7*time.Second, color.White, grey),
+				interRange(300, 350, color.White, grey),
+			},
+			nil,
+		},
+		// Style foreground, background or both
+		{
+			[]intertitle.Range{
+				interRange(100, 150, color.White, grey),
+				interRange(300, 350, color.White, grey),
+			},
+			[]correct.Correction{
+				{Action: correct.Restyle, At: 5 * time.Second, Foreground: sepia},
+				{Action: correct.Restyle, At: 13 * time.Second, Background: color.Black},
+			},
+			[]intertitle.Range{
+				interRange(100, 150, sepia, grey),
+				interRange(300, 350, color.White, color.Black),
+			},
+			nil,
+		},
+		// Skipped, since no range is at the time, or the adjustment ends
+		//  before it starts
+		{
+			[]intertitle.Range{interRange(100, 150, color.White, grey)},
+			[]correct.Correction{
+				{Action: correct.Delete, At: 10 * time.Second},
+				{Action: correct.Restyle, At: 2 * time.Second, Foreground: sepia},
+				{Action: correct.Adjust, At: 5 * time.Second, Start: duration(5 * time.Second)},
+				{Action: correct.Adjust, At: 5 * time.Second, End: duration(3 * time.Second)},
+			},
+			[]intertitle.Range{timedRange(125, 150, 5*time.Second, 6*time.Second, color.White, grey)},
+			[]int{0, 1, 3},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, actualSkipped := correct.Apply(test.ranges, test.corrections, 25.0)
+
+			// Verify result
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+			if !reflect.DeepEqual(actualSkipped, test.expectedSkipped) {
+				t.Errorf("Skipped differs. Actual: %v, Expected: %v", actualSkipped, test.expectedSkipped)
+			}
+		})
+	}
+}
+
+func TestApply_DoesNotModifyRanges(t *testing.T) {
+	// Setup fixture
+	fixture := []intertitle.Range{interRange(100, 150, color.White, color.Black)}
+	corrections := []correct.Correction{
+		{Action: correct.Restyle, At: 5 * time.Second, Foreground: color.Black},
+	}
+
+	// Exercise SUT
+	correct.Apply(fixture, corrections, 25.0)
+
+	// Verify result
+	if fixture[0].Style.ForegroundColor != color.White {
+		t.Errorf("SUT modified its input: %+v", fixture[0])
+	}
+}
+
+func writeFixture(t *testing.T, text string) string {
+	path := "/tmp/cabiria/correctionsTest.yaml"
+	if err := ioutil.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatalf("Could not write fixture: %v", err)
+	}
+	return path
+}
+
+func duration(d time.Duration) *time.Duration {
+	return &d
+}
+
+func interRange(start, end int, foreground, background color.Color) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		FPS:        25.0,
+		Style: intertitle.Style{
+			ForegroundColor: foreground,
+			BackgroundColor: background,
+		},
+	}
+}
+
+func timedRange(start, end int, startTime, endTime time.Duration, foreground, background color.Color) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		FPS:        25.0,
+		Timed:      true,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Style: intertitle.Style{
+			ForegroundColor: foreground,
+			BackgroundColor: background,
+		},
+	}
+}
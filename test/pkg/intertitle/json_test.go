@@ -0,0 +1,93 @@
+package intertitle_test
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/intertitle"
+)
+
+func TestNewJSONRange(t *testing.T) {
+	// Setup fixture
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	grey := color.RGBA{R: 0x19, G: 0x19, B: 0x19, A: 0xFF}
+	var tests = []struct {
+		fixture  intertitle.Range
+		expected intertitle.JSONRange
+	}{
+		{
+			interRangeWithStyle(100, 159, 25.0, style(white, grey)),
+			intertitle.JSONRange{StartFrame: 100, EndFrame: 159, FPS: 25.0, Start: 4.0, End: 6.36, Foreground: "#ffffff", Background: "#191919"},
+		},
+		{
+			timedRangeWithStyle(100, 159, 0.0, 4100*time.Millisecond, 6500*time.Millisecond, style(grey, white)),
+			intertitle.JSONRange{StartFrame: 100, EndFrame: 159, Timed: true, Start: 4.1, End: 6.5, Foreground: "#191919", Background: "#ffffff"},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := intertitle.NewJSONRange(test.fixture)
+
+			// Verify result
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestJSONRange_Range_RoundTrip(t *testing.T) {
+	// Setup fixture
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	grey := color.RGBA{R: 0x19, G: 0x19, B: 0x19, A: 0xFF}
+	var tests = []intertitle.Range{
+		interRangeWithStyle(100, 159, 25.0, style(white, grey)),
+		timedRangeWithStyle(100, 159, 0.0, 4100*time.Millisecond, 6500*time.Millisecond, style(grey, white)),
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, err := intertitle.NewJSONRange(test).Range()
+
+			// Verify result
+			if err != nil {
+				t.Fatalf("Encountered error: %v", err)
+			}
+			if !reflect.DeepEqual(intertitle.NewJSONRange(actual), intertitle.NewJSONRange(test)) {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test)
+			}
+			if actual.StartFrame != test.StartFrame || actual.EndFrame != test.EndFrame || actual.Timed != test.Timed ||
+				actual.StartTime != test.StartTime || actual.EndTime != test.EndTime {
+				t.Errorf("Timing differs. Actual: %+v, Expected: %+v", actual, test)
+			}
+		})
+	}
+}
+
+func TestJSONRange_Range_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []intertitle.JSONRange{
+		{StartFrame: 0, EndFrame: 1, FPS: 25.0, Foreground: "white", Background: "#191919"},
+		{StartFrame: 0, EndFrame: 1, FPS: 25.0, Foreground: "#ffffff", Background: "#1919"},
+		{StartFrame: 2, EndFrame: 1, FPS: 25.0, Foreground: "#ffffff", Background: "#191919"},
+		{StartFrame: 0, EndFrame: 1, Timed: true, Start: 2.0, End: 1.0, Foreground: "#ffffff", Background: "#191919"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			_, err := test.Range()
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -151,6 +151,104 @@ func TestPredictSingle_WhenNilFrame(t *testing.T) {
 	}
 }
 
+func TestPredictProba(t *testing.T) {
+	// Setup fixture
+	predictor := testPredictor()
+	frames := []image.Image{whiteImage(), blackImage()}
+
+	// Exercise SUT
+	actual, err := predictor.PredictProba(frames)
+
+	// Verify result
+	if err != nil {
+		t.Errorf("SUT returned an error: %v", err)
+	}
+	expected := []float64{0.6, 0.3}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %v, Expected %v", actual, expected)
+	}
+}
+
+func TestPredictProbaSingle(t *testing.T) {
+	// Setup fixture
+	predictor := testPredictor()
+	var tests = []struct {
+		frame    image.Image
+		expected float64
+	}{
+		{
+			whiteImage(),
+			0.6,
+		},
+		{
+			blackImage(),
+			0.3,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, err := predictor.PredictProbaSingle(test.frame)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT returned an error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %v, Expected %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestPredictProbaSingle_WhenNilFrame(t *testing.T) {
+	// Setup fixture
+	predictor := testPredictor()
+
+	// Exercise SUT
+	_, err := predictor.PredictProbaSingle(nil)
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+func TestMeanConfidence(t *testing.T) {
+	// Setup fixture
+	confidences := []float64{0.0, 0.5, 1.0, 1.0, 0.25}
+	var tests = []struct {
+		start    int
+		end      int
+		expected float64
+	}{
+		{0, 0, 0.0},
+		{1, 3, 2.5 / 3.0},
+		{2, 4, 0.75},
+		// Partly past the end
+		{3, 10, 0.625},
+		// Wholly past the end
+		{8, 10, 0.0},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := intertitle.MeanConfidence(confidences, intertitle.Range{
+				StartFrame: test.start,
+				EndFrame:   test.end,
+				FPS:        25.0,
+			})
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %v, Expected %v", actual, test.expected)
+			}
+		})
+	}
+}
+
 func testPredictor() intertitle.Predictor {
 	dummy := test.DummyPredictor{}
 	dummy.Fit([]ml.Sample{
@@ -39,6 +39,14 @@ func TestValid(t *testing.T) {
 			interRange(1, 0, 1.0),
 			false,
 		},
+		{
+			timedRange(0, 1, 0.0, -time.Second, time.Second),
+			false,
+		},
+		{
+			timedRange(0, 1, 0.0, 2*time.Second, time.Second),
+			false,
+		},
 		// Valid cases
 		{
 			interRange(0, 0, 1.0),
@@ -48,6 +56,10 @@ func TestValid(t *testing.T) {
 			interRange(1, 2, 2.5),
 			true,
 		},
+		{
+			timedRange(1, 2, 0.0, time.Second, 2*time.Second),
+			true,
+		},
 	}
 
 	for i, test := range tests {
@@ -85,6 +97,11 @@ func TestStart(t *testing.T) {
 			interRange(10, 20, 0.5),
 			timestamp(0, 0, 20, 0),
 		},
+		// Timestamps take precedence over FPS
+		{
+			timedRange(10, 20, 2.0, 4200*time.Millisecond, 9*time.Second),
+			timestamp(0, 0, 4, 200),
+		},
 	}
 
 	for i, test := range tests {
@@ -122,6 +139,11 @@ func TestEnd(t *testing.T) {
 			interRange(10, 20, 0.5),
 			timestamp(0, 0, 40, 0),
 		},
+		// Timestamps take precedence over FPS
+		{
+			timedRange(10, 20, 2.0, 4200*time.Millisecond, 9*time.Second),
+			timestamp(0, 0, 9, 0),
+		},
 	}
 
 	for i, test := range tests {
@@ -182,6 +204,13 @@ func TestTransformToNew(t *testing.T) {
 			timestamp(0, 0, 4, 0),
 			interRange(7, 10, 2.5),
 		},
+		// Timed case
+		{
+			timedRange(5, 6, 2.0, 2500*time.Millisecond, 3*time.Second),
+			timestamp(0, 0, 3, 100),
+			timestamp(0, 0, 4, 0),
+			timedRange(6, 8, 2.0, 3100*time.Millisecond, 4*time.Second),
+		},
 	}
 
 	for i, test := range tests {
@@ -306,7 +335,7 @@ func TestMapRanges(t *testing.T) {
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
 			// Exercise SUT
-			actual, err := intertitle.MapRanges(test.intertitles, test.fps, framePaths())
+			actual, err := intertitle.MapRanges(test.intertitles, test.fps, nil, intertitle.PNGFrames(framePaths()))
 
 			// Verify result
 			if err != nil {
@@ -319,6 +348,43 @@ func TestMapRanges(t *testing.T) {
 	}
 }
 
+func TestMapRanges_WithTimestamps(t *testing.T) {
+	// Setup fixture
+	intertitles := intertitles(1, 0, 1, 1, 0)
+	timestamps := []time.Duration{
+		0,
+		40 * time.Millisecond,
+		120 * time.Millisecond,
+		130 * time.Millisecond,
+		200 * time.Millisecond,
+	}
+	expected := interRanges(
+		timedRangeWithStyle(0, 0, 25.0, 0, 0, style(white(), black())),
+		timedRangeWithStyle(2, 3, 25.0, 120*time.Millisecond, 130*time.Millisecond, style(white(), black())),
+	)
+
+	// Exercise SUT
+	actual, err := intertitle.MapRanges(intertitles, 25.0, timestamps, intertitle.PNGFrames(framePaths()))
+
+	// Verify result
+	if err != nil {
+		t.Errorf("SUT returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %v, Expected %v", actual, expected)
+	}
+}
+
+func TestMapRanges_WhenTimestampsMismatch(t *testing.T) {
+	// Exercise SUT
+	_, err := intertitle.MapRanges(intertitles(1, 0), 25.0, []time.Duration{0}, intertitle.PNGFrames(framePaths()))
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
 func framePaths() []string {
 	var result []string
 	for i := 0; i < 10; i++ {
@@ -344,6 +410,22 @@ func interRangeWithStyle(start, end int, fps float64, style intertitle.Style) in
 	}
 }
 
+func timedRange(start, end int, fps float64, startTime, endTime time.Duration) intertitle.Range {
+	return timedRangeWithStyle(start, end, fps, startTime, endTime, intertitle.Style{})
+}
+
+func timedRangeWithStyle(start, end int, fps float64, startTime, endTime time.Duration, style intertitle.Style) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		FPS:        fps,
+		Timed:      true,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Style:      style,
+	}
+}
+
 func interRanges(interRanges ...intertitle.Range) []intertitle.Range {
 	result := make([]intertitle.Range, 0)
 	return append(result, interRanges...)
@@ -0,0 +1,179 @@
+package read_test
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/intertitle/read"
+	"github.com/liampulles/cabiria/pkg/intertitle/write"
+)
+
+func TestJSON(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		text     string
+		expected []intertitle.Range
+	}{
+		// No ranges
+		{
+			`{"intertitles": []}`,
+			[]intertitle.Range{},
+		},
+		// Many ranges
+		{
+			`{
+			  "intertitles": [
+			    {"start_frame": 100, "end_frame": 159, "fps": 25, "timed": false, "start": 4, "end": 6.36,
+			     "foreground": "#ffffff", "background": "#000000"},
+			    {"start_frame": 250, "end_frame": 300, "fps": 0, "timed": true, "start": 1.2, "end": 3,
+			     "foreground": "#ffffff", "background": ""}
+			  ]
+			}`,
+			[]intertitle.Range{
+				interRange(100, 159, 25.0, white(), black()),
+				timedRange(250, 300, 1200*time.Millisecond, 3*time.Second, white(), nil),
+			},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			path := writeFixture(t, "rangesReadTest.json", test.text)
+
+			// Exercise SUT
+			actual, err := read.JSON(path)
+
+			// Verify result
+			if err != nil {
+				t.Fatalf("SUT returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestJSON_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []string{
+		// Not JSON
+		`intertitles`,
+		// Bad color
+		`{"intertitles": [{"start_frame": 1, "end_frame": 2, "fps": 25, "foreground": "white"}]}`,
+		// No FPS, and not timed
+		`{"intertitles": [{"start_frame": 1, "end_frame": 2}]}`,
+		// Ends before it starts
+		`{"intertitles": [{"start_frame": 2, "end_frame": 1, "fps": 25}]}`,
+		`{"intertitles": [{"start_frame": 1, "end_frame": 2, "timed": true, "start": 2, "end": 1}]}`,
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			path := writeFixture(t, "rangesReadTest.json", test)
+
+			// Exercise SUT
+			_, err := read.JSON(path)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to throw an error")
+			}
+		})
+	}
+}
+
+func TestJSON_WhenWrittenByWrite(t *testing.T) {
+	// Setup fixture
+	fixture := []intertitle.Range{
+		interRange(100, 159, 24000.0/1001.0, white(), color.RGBA{R: 25, G: 25, B: 25, A: 255}),
+		timedRange(250, 300, 10427083*time.Microsecond, 12512500*time.Microsecond, white(), black()),
+	}
+	path := "/tmp/cabiria/rangesRoundTripTest.json"
+	if err := write.JSON(fixture, path); err != nil {
+		t.Fatalf("Could not write fixture: %v", err)
+	}
+
+	// Exercise SUT
+	actual, err := read.JSON(path)
+
+	// Verify result
+	if err != nil {
+		t.Fatalf("SUT returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, fixture) {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, fixture)
+	}
+}
+
+func TestRanges_WhenExtensionIsUnknown(t *testing.T) {
+	// Setup fixture
+	path := writeFixture(t, "rangesReadTest.csv", "index,start_frame,end_frame,start,end,foreground,background\n")
+
+	// Exercise SUT
+	_, err := read.Ranges(path)
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to throw an error")
+	}
+}
+
+func TestRanges_WhenFileIsMissing(t *testing.T) {
+	// Exercise SUT
+	_, err := read.Ranges("/tmp/cabiria/does.not.exist.json")
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to throw an error")
+	}
+}
+
+func writeFixture(t *testing.T, name string, text string) string {
+	path := "/tmp/cabiria/" + name
+	if err := ioutil.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatalf("Could not write fixture: %v", err)
+	}
+	return path
+}
+
+func white() color.Color {
+	return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+}
+
+func black() color.Color {
+	return color.RGBA{A: 255}
+}
+
+func interRange(start, end int, fps float64, foreground, background color.Color) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		FPS:        fps,
+		Style: intertitle.Style{
+			ForegroundColor: foreground,
+			BackgroundColor: background,
+		},
+	}
+}
+
+func timedRange(start, end int, startTime, endTime time.Duration, foreground, background color.Color) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		Timed:      true,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Style: intertitle.Style{
+			ForegroundColor: foreground,
+			BackgroundColor: background,
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package intertitle_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/intertitle"
+)
+
+func TestSnapRanges(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		ranges    []intertitle.Range
+		starts    []int
+		ends      []int
+		tolerance int
+		expected  []intertitle.Range
+	}{
+		// No boundaries
+		{
+			[]intertitle.Range{snapRange(10, 20)},
+			nil,
+			nil,
+			3,
+			[]intertitle.Range{snapRange(10, 20)},
+		},
+		// Within tolerance -> Snapped to closest
+		{
+			[]intertitle.Range{snapRange(10, 20)},
+			[]int{5, 8, 11},
+			[]int{17, 22, 30},
+			3,
+			[]intertitle.Range{snapRange(11, 22)},
+		},
+		// Tie -> Earlier
+		{
+			[]intertitle.Range{snapRange(10, 20)},
+			[]int{8, 12},
+			[]int{19, 21},
+			3,
+			[]intertitle.Range{snapRange(8, 19)},
+		},
+		// Outside tolerance, or tolerance is zero -> Unchanged
+		{
+			[]intertitle.Range{snapRange(10, 20)},
+			[]int{6},
+			[]int{24},
+			3,
+			[]intertitle.Range{snapRange(10, 20)},
+		},
+		{
+			[]intertitle.Range{snapRange(10, 20)},
+			[]int{9},
+			[]int{21},
+			0,
+			[]intertitle.Range{snapRange(10, 20)},
+		},
+		// Would overlap or invert -> Closest which does not, earlier ranges first
+		{
+			[]intertitle.Range{snapRange(10, 20), snapRange(22, 30), snapRange(40, 42)},
+			[]int{19, 21, 43},
+			[]int{21, 23, 39},
+			3,
+			[]intertitle.Range{snapRange(10, 21), snapRange(22, 30), snapRange(40, 42)},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := intertitle.SnapRanges(test.ranges, test.starts, test.ends, test.tolerance, nil)
+
+			// Verify result
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestSnapRanges_WhenTimed(t *testing.T) {
+	// Setup fixture
+	timestamps := make([]time.Duration, 30)
+	for i := range timestamps {
+		timestamps[i] = time.Duration(i) * 40 * time.Millisecond
+	}
+	fixture := []intertitle.Range{timedSnapRange(10, 20, timestamps)}
+
+	// Exercise SUT
+	actual := intertitle.SnapRanges(fixture, []int{9}, []int{22}, 3, timestamps)
+	actualWithoutTimestamps := intertitle.SnapRanges(fixture, []int{9}, []int{22}, 3, nil)
+
+	// Verify result
+	expected := []intertitle.Range{timedSnapRange(9, 22, timestamps)}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, expected)
+	}
+	if !reflect.DeepEqual(actualWithoutTimestamps, fixture) {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actualWithoutTimestamps, fixture)
+	}
+}
+
+func snapRange(start, end int) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		FPS:        25.0,
+	}
+}
+
+func timedSnapRange(start, end int, timestamps []time.Duration) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		FPS:        25.0,
+		Timed:      true,
+		StartTime:  timestamps[start],
+		EndTime:    timestamps[end],
+	}
+}
@@ -0,0 +1,262 @@
+package write_test
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/intertitle"
+	"github.com/liampulles/cabiria/pkg/intertitle/write"
+)
+
+func TestJSON(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		ranges   []intertitle.Range
+		expected string
+	}{
+		// No ranges
+		{
+			ranges(),
+			`{
+  "intertitles": []
+}
+`,
+		},
+		// Many ranges
+		{
+			ranges(
+				interRange(100, 159, 25.0, color.White, color.Black),
+				timedRange(250, 300, 1200*time.Millisecond, 3*time.Second, color.White, nil),
+			),
+			`{
+  "intertitles": [
+    {
+      "start_frame": 100,
+      "end_frame": 159,
+      "fps": 25,
+      "timed": false,
+      "start": 4,
+      "end": 6.36,
+      "foreground": "#ffffff",
+      "background": "#000000"
+    },
+    {
+      "start_frame": 250,
+      "end_frame": 300,
+      "fps": 0,
+      "timed": true,
+      "start": 1.2,
+      "end": 3,
+      "foreground": "#ffffff",
+      "background": ""
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			err := write.JSON(test.ranges, "/tmp/cabiria/rangesTest.json")
+
+			// Verify result
+			verifyWritten(t, err, "/tmp/cabiria/rangesTest.json", test.expected)
+		})
+	}
+}
+
+func TestCSV(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		ranges   []intertitle.Range
+		expected string
+	}{
+		// No ranges
+		{
+			ranges(),
+			"index,start_frame,end_frame,start,end,foreground,background\n",
+		},
+		// Many ranges
+		{
+			ranges(
+				interRange(100, 159, 25.0, color.White, color.Black),
+				interRange(1000, 1100, 24000.0/1001.0, color.White, color.Black),
+			),
+			"index,start_frame,end_frame,start,end,foreground,background\n" +
+				"1,100,159,4.000,6.400,#ffffff,#000000\n" +
+				"2,1000,1100,41.708,45.921,#ffffff,#000000\n",
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			err := write.CSV(test.ranges, "/tmp/cabiria/rangesTest.csv")
+
+			// Verify result
+			verifyWritten(t, err, "/tmp/cabiria/rangesTest.csv", test.expected)
+		})
+	}
+}
+
+func TestFFMetadata(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		ranges   []intertitle.Range
+		expected string
+	}{
+		// No ranges
+		{
+			ranges(),
+			";FFMETADATA1\n",
+		},
+		// Many ranges
+		{
+			ranges(
+				interRange(100, 159, 25.0, color.White, color.Black),
+				timedRange(250, 300, 1200*time.Millisecond, 3*time.Second, color.White, color.Black),
+			),
+			`;FFMETADATA1
+
+[CHAPTER]
+TIMEBASE=1/1000
+START=4000
+END=6400
+title=Intertitle 1
+
+[CHAPTER]
+TIMEBASE=1/1000
+START=1200
+END=3000
+title=Intertitle 2
+`,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			err := write.FFMetadata(test.ranges, "/tmp/cabiria/rangesTest.ffmetadata")
+
+			// Verify result
+			verifyWritten(t, err, "/tmp/cabiria/rangesTest.ffmetadata", test.expected)
+		})
+	}
+}
+
+func TestMatroskaChapters(t *testing.T) {
+	// Setup fixture
+	fixture := ranges(
+		timedRange(0, 10, 3723450*time.Millisecond, 3725*time.Second, color.White, color.Black),
+		interRange(100, 159, 25.0, color.White, color.Black),
+	)
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE Chapters SYSTEM "matroskachapters.dtd">
+<Chapters>
+  <EditionEntry>
+    <ChapterAtom>
+      <ChapterTimeStart>01:02:03.450000000</ChapterTimeStart>
+      <ChapterTimeEnd>01:02:05.000000000</ChapterTimeEnd>
+      <ChapterDisplay>
+        <ChapterString>Intertitle 1</ChapterString>
+        <ChapterLanguage>eng</ChapterLanguage>
+      </ChapterDisplay>
+    </ChapterAtom>
+    <ChapterAtom>
+      <ChapterTimeStart>00:00:04.000000000</ChapterTimeStart>
+      <ChapterTimeEnd>00:00:06.400000000</ChapterTimeEnd>
+      <ChapterDisplay>
+        <ChapterString>Intertitle 2</ChapterString>
+        <ChapterLanguage>eng</ChapterLanguage>
+      </ChapterDisplay>
+    </ChapterAtom>
+  </EditionEntry>
+</Chapters>
+`
+
+	// Exercise SUT
+	err := write.MatroskaChapters(fixture, "/tmp/cabiria/rangesTest.xml")
+
+	// Verify result
+	verifyWritten(t, err, "/tmp/cabiria/rangesTest.xml", expected)
+}
+
+func TestRanges_ByExtension(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		path     string
+		expected string
+	}{
+		{"/tmp/cabiria/rangesTest.json", "{\n  \"intertitles\": []\n}\n"},
+		{"/tmp/cabiria/rangesTest.CSV", "index,start_frame,end_frame,start,end,foreground,background\n"},
+		{"/tmp/cabiria/rangesTest.ffmetadata", ";FFMETADATA1\n"},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			err := write.Ranges(ranges(), test.path)
+
+			// Verify result
+			verifyWritten(t, err, test.path, test.expected)
+		})
+	}
+}
+
+func TestRanges_WhenExtensionIsUnknown(t *testing.T) {
+	// Exercise SUT
+	err := write.Ranges(ranges(), "/tmp/cabiria/rangesTest.srt")
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to throw an error")
+	}
+}
+
+func verifyWritten(t *testing.T, err error, path string, expected string) {
+	if err != nil {
+		t.Fatalf("SUT returned an error: %v", err)
+	}
+	actual, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Could not read result: %v", err)
+	}
+	if string(actual) != expected {
+		t.Errorf("Result differs. Actual:\n%sExpected:\n%s", actual, expected)
+	}
+}
+
+func ranges(ranges ...intertitle.Range) []intertitle.Range {
+	return ranges
+}
+
+func interRange(start, end int, fps float64, foreground, background color.Color) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		FPS:        fps,
+		Style: intertitle.Style{
+			ForegroundColor: foreground,
+			BackgroundColor: background,
+		},
+	}
+}
+
+func timedRange(start, end int, startTime, endTime time.Duration, foreground, background color.Color) intertitle.Range {
+	return intertitle.Range{
+		StartFrame: start,
+		EndFrame:   end,
+		Timed:      true,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Style: intertitle.Style{
+			ForegroundColor: foreground,
+			BackgroundColor: background,
+		},
+	}
+}
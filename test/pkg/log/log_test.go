@@ -0,0 +1,153 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/log"
+)
+
+func TestParseLevel_WhenValid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		name     string
+		expected log.Level
+	}{
+		{"debug", log.Debug},
+		{"info", log.Info},
+		{"warn", log.Warn},
+		{"error", log.Error},
+		{"WARN", log.Warn},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, err := log.ParseLevel(test.name)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT threw an error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseLevel_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []string{
+		"",
+		"verbose",
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			_, err := log.ParseLevel(test)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to throw an error")
+			}
+		})
+	}
+}
+
+func TestLogger(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		level    log.Level
+		log      func(logger *log.Logger)
+		expected string
+	}{
+		// Levels
+		{
+			log.Debug,
+			func(logger *log.Logger) { logger.Debug("Probed") },
+			"time=2020-05-01T10:00:00Z level=debug msg=Probed\n",
+		},
+		{
+			log.Info,
+			func(logger *log.Logger) { logger.Debug("Probed") },
+			"",
+		},
+		{
+			log.Info,
+			func(logger *log.Logger) { logger.Warn("Odd") },
+			"time=2020-05-01T10:00:00Z level=warn msg=Odd\n",
+		},
+		{
+			log.Error,
+			func(logger *log.Logger) { logger.Info("Saved") },
+			"",
+		},
+		{
+			log.Error,
+			func(logger *log.Logger) { logger.Error("Failed") },
+			"time=2020-05-01T10:00:00Z level=error msg=Failed\n",
+		},
+		// Values
+		{
+			log.Info,
+			func(logger *log.Logger) {
+				logger.Info("Saved ASS", "path", "film.ass", "count", 3, "took", 1500*time.Millisecond)
+			},
+			"time=2020-05-01T10:00:00Z level=info msg=\"Saved ASS\" path=film.ass count=3 took=1.5s\n",
+		},
+		{
+			log.Info,
+			func(logger *log.Logger) {
+				logger.Info("Failed", "error", errors.New("no such file"), "text", "a=\"b\"", "empty", "")
+			},
+			"time=2020-05-01T10:00:00Z level=info msg=Failed error=\"no such file\" text=\"a=\\\"b\\\"\" empty=\"\"\n",
+		},
+		{
+			log.Info,
+			func(logger *log.Logger) { logger.Info("Odd", "key") },
+			"time=2020-05-01T10:00:00Z level=info msg=Odd key=(MISSING)\n",
+		},
+		// Values attached with With come first
+		{
+			log.Info,
+			func(logger *log.Logger) {
+				logger.With("film", "a.mkv").Info("Saved ASS", "path", "a.ass")
+			},
+			"time=2020-05-01T10:00:00Z level=info msg=\"Saved ASS\" film=a.mkv path=a.ass\n",
+		},
+		{
+			log.Info,
+			func(logger *log.Logger) {
+				film := logger.With("film", "a.mkv")
+				film.With("job", 1).Warn("Odd")
+				film.Info("Saved")
+			},
+			"time=2020-05-01T10:00:00Z level=warn msg=Odd film=a.mkv job=1\n" +
+				"time=2020-05-01T10:00:00Z level=info msg=Saved film=a.mkv\n",
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			var out bytes.Buffer
+			now := func() time.Time {
+				return time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+			}
+			logger := log.NewWithClock(&out, test.level, now)
+
+			// Exercise SUT
+			test.log(logger)
+
+			// Verify result
+			if out.String() != test.expected {
+				t.Errorf("Result differs. Actual: %q, Expected: %q", out.String(), test.expected)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cluster_test
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -397,3 +398,23 @@ func datum(val ...float64) ml.Datum {
 func counts(val ...int) []int {
 	return val
 }
+
+func TestKMeansClassifier_FitContext_WhenCancelled(t *testing.T) {
+	// Setup fixture
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	input := datums(
+		datum(1.0),
+		datum(2.0),
+		datum(10.0),
+		datum(11.0),
+	)
+
+	// Exercise SUT
+	_, _, err := cluster.NewKMeansClassifier(2, 100).FitContext(ctx, input)
+
+	// Verify result
+	if err != context.Canceled {
+		t.Errorf("Result differs. Actual error: %v, Expected: %v", err, context.Canceled)
+	}
+}
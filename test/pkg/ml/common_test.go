@@ -382,6 +382,14 @@ func (mc MockClassifier) PredictSingle(input ml.Datum) (ml.Datum, error) {
 	return []float64{1}, nil
 }
 
+func (mc MockClassifier) PredictProba(input []ml.Datum) ([]ml.Datum, error) {
+	panic(fmt.Errorf("SUT should not call PredictProba"))
+}
+
+func (mc MockClassifier) PredictProbaSingle(input ml.Datum) (ml.Datum, error) {
+	panic(fmt.Errorf("SUT should not call PredictProbaSingle"))
+}
+
 func (mc MockClassifier) Save(path string) error {
 	panic(fmt.Errorf("SUT should not call Save"))
 }
@@ -363,6 +363,102 @@ func TestKNNClassifier_Predict_WhenClassifierAndInputIsValid_ExpectPass(t *testi
 	}
 }
 
+func TestKNNClassifier_PredictProbaSingle_WhenClassifierAndInputIsValid_ExpectPass(t *testing.T) {
+	// Setup fixture, expectations
+	var tests = []struct {
+		k            uint
+		inputFixture ml.Datum
+		expected     ml.Datum
+	}{
+		// Single neighbour -> Expect its class
+		{
+			1,
+			topLeftInput(0.1, 0.0),
+			topLeftClass(),
+		},
+		// Two neighbours of different classes -> Expect an even split
+		{
+			2,
+			topLeftInput(0.1, 0.0),
+			[]float64{0, 0.5},
+		},
+		// All neighbours -> Expect the fraction of each class
+		{
+			4,
+			topLeftInput(0.1, 0.0),
+			[]float64{0.5, 0.5},
+		},
+		// More neighbours than samples -> Expect all samples to vote
+		{
+			10,
+			bottomRightInput(0.0, 0.0),
+			[]float64{0.5, 0.5},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			knn := ml.NewKNNClassifier(test.k)
+			knn.Fit([]ml.Sample{
+				sample(bottomRightInput(0.0, 0.0), bottomRightClass()),
+				sample(topRightInput(0.0, 0.0), topRightClass()),
+				sample(topLeftInput(0.0, 0.0), topLeftClass()),
+				sample(bottomLeftInput(0.0, 0.0), bottomLeftClass()),
+			})
+
+			// Exercise SUT
+			actual, err := knn.PredictProbaSingle(test.inputFixture)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT threw error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Unexpected result.\nExpected: %v\nActual: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestKNNClassifier_PredictProba_WhenClassifierAndInputIsValid_ExpectPass(t *testing.T) {
+	// Setup fixture
+	knn := ml.NewKNNClassifier(3)
+	knn.Fit([]ml.Sample{
+		sample([]float64{0.0}, []float64{0}),
+		sample([]float64{1.0}, []float64{0}),
+		sample([]float64{2.0}, []float64{1}),
+		sample([]float64{3.0}, []float64{1}),
+		sample([]float64{4.0}, []float64{1}),
+	})
+	input := []ml.Datum{{0.0}, {2.0}, {4.0}}
+	expected := []ml.Datum{{1.0 / 3.0}, {2.0 / 3.0}, {1.0}}
+
+	// Exercise SUT
+	actual, err := knn.PredictProba(input)
+
+	// Verify result
+	if err != nil {
+		t.Errorf("SUT threw error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected result.\nExpected: %v\nActual: %v", expected, actual)
+	}
+}
+
+func TestKNNClassifier_PredictProbaSingle_WhenNotFitted_ExpectFail(t *testing.T) {
+	// Setup fixture
+	knn := ml.NewKNNClassifier(3)
+
+	// Exercise SUT
+	_, err := knn.PredictProbaSingle([]float64{0.0})
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to throw error, but none thrown")
+	}
+}
+
 func sample(input ml.Datum, output ml.Datum) ml.Sample {
 	return ml.Sample{
 		Input:  input,
@@ -301,6 +301,26 @@ func TestPredictSingle_WhenNotFittedForInput(t *testing.T) {
 	}
 }
 
+func TestPredictProbaSingle(t *testing.T) {
+	// Setup fixture
+	fixture := dummy(map[string]ml.Datum{
+		"4.2":        datum(0.25),
+		"1.01,3.023": datum(0.5, 1.0),
+	})
+
+	// Exercise SUT
+	actual, err := fixture.PredictProba([]ml.Datum{datum(4.2), datum(1.01, 3.023)})
+
+	// Verify result
+	if err != nil {
+		t.Errorf("SUT threw an error: %v", err)
+	}
+	expected := []ml.Datum{datum(0.25), datum(0.5, 1.0)}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected result.\nExpected: %v\nActual: %v", expected, actual)
+	}
+}
+
 func TestSave(t *testing.T) {
 	// Setup fixture
 	predictor := dummy(map[string]ml.Datum{
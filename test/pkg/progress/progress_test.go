@@ -0,0 +1,236 @@
+package progress_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/progress"
+)
+
+func TestTerminal(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		total    int
+		updates  []int
+		expected []string
+	}{
+		// Unknown total
+		{
+			0,
+			[]int{},
+			[]string{"Stage...", "Stage: DONE in 0:01\n"},
+		},
+		{
+			0,
+			[]int{20},
+			[]string{"Stage...", "Stage: 20 (20.0/s)", "Stage: DONE in 0:02\n"},
+		},
+		// Known total
+		{
+			100,
+			[]int{25, 50},
+			[]string{
+				"Stage...",
+				"Stage: 25% (25/100, 25.0/s, ETA 0:03)",
+				"Stage: 50% (50/100, 25.0/s, ETA 0:02)",
+				"Stage: DONE in 0:03\n",
+			},
+		},
+		// Long ETA
+		{
+			7200,
+			[]int{1},
+			[]string{"Stage...", "Stage: 0% (1/7200, 1.0/s, ETA 1:59:59)", "Stage: DONE in 0:02\n"},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			var out bytes.Buffer
+			clock := &fakeClock{}
+			terminal := progress.NewTerminalWithClock(&out, clock.now)
+
+			// Exercise SUT
+			terminal.Start("Stage", test.total)
+			for _, done := range test.updates {
+				clock.advance(time.Second)
+				terminal.Update("Stage", done)
+			}
+			clock.advance(time.Second)
+			terminal.Finish("Stage")
+
+			// Verify result
+			actual := lines(out.String())
+			if !equal(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %q, Expected: %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestTerminal_WithConcurrentStages(t *testing.T) {
+	// Setup fixture
+	var out bytes.Buffer
+	clock := &fakeClock{}
+	terminal := progress.NewTerminalWithClock(&out, clock.now)
+	expected := []string{
+		"First...",
+		"First... | Second...",
+		"First: 1 (1.0/s) | Second...",
+		"First: DONE in 0:02\n",
+		"Second...",
+		"Second: 2 (0.7/s)",
+		"Second: DONE in 0:04\n",
+	}
+
+	// Exercise SUT
+	terminal.Start("First", 0)
+	terminal.Start("Second", 0)
+	clock.advance(time.Second)
+	terminal.Update("First", 1)
+	clock.advance(time.Second)
+	terminal.Finish("First")
+	clock.advance(time.Second)
+	terminal.Update("Second", 2)
+	clock.advance(time.Second)
+	terminal.Finish("Second")
+
+	// Verify result
+	actual := lines(out.String())
+	if !equal(actual, expected) {
+		t.Errorf("Result differs. Actual: %q, Expected: %q", actual, expected)
+	}
+}
+
+func TestTerminal_WhenUpdatedOften(t *testing.T) {
+	// Setup fixture
+	var out bytes.Buffer
+	clock := &fakeClock{}
+	terminal := progress.NewTerminalWithClock(&out, clock.now)
+	// Only redrawn once the interval has passed
+	expected := []string{"Stage...", "Stage: 2 (20.0/s)"}
+
+	// Exercise SUT
+	terminal.Start("Stage", 0)
+	for i := 1; i <= 3; i++ {
+		clock.advance(50 * time.Millisecond)
+		terminal.Update("Stage", i)
+	}
+
+	// Verify result
+	actual := lines(out.String())
+	if !equal(actual, expected) {
+		t.Errorf("Result differs. Actual: %q, Expected: %q", actual, expected)
+	}
+}
+
+func TestTerminal_WhenStageIsUnknown(t *testing.T) {
+	// Setup fixture
+	var out bytes.Buffer
+	terminal := progress.NewTerminal(&out)
+
+	// Exercise SUT
+	terminal.Update("Unknown", 1)
+	terminal.Finish("Unknown")
+
+	// Verify result
+	if out.Len() != 0 {
+		t.Errorf("Expected no output, but got: %q", out.String())
+	}
+}
+
+func TestTerminal_Writer(t *testing.T) {
+	// Setup fixture
+	var out bytes.Buffer
+	clock := &fakeClock{}
+	terminal := progress.NewTerminalWithClock(&out, clock.now)
+	expected := "before\n" +
+		"\r\033[KStage..." +
+		"\r\033[Kduring\n" +
+		"\r\033[KStage..." +
+		"\r\033[KStage: DONE in 0:01\n" +
+		"after\n"
+
+	// Exercise SUT
+	writer := terminal.Writer()
+	fmt.Fprint(writer, "before\n")
+	terminal.Start("Stage", 0)
+	fmt.Fprint(writer, "during\n")
+	clock.advance(time.Second)
+	terminal.Finish("Stage")
+	fmt.Fprint(writer, "after\n")
+
+	// Verify result
+	if out.String() != expected {
+		t.Errorf("Result differs. Actual: %q, Expected: %q", out.String(), expected)
+	}
+}
+
+type fakeClock struct {
+	elapsed time.Duration
+}
+
+func (fc *fakeClock) now() time.Time {
+	return time.Time{}.Add(fc.elapsed)
+}
+
+func (fc *fakeClock) advance(d time.Duration) {
+	fc.elapsed += d
+}
+
+// lines splits terminal output into the lines drawn.
+func lines(output string) []string {
+	return strings.Split(output, "\r\033[K")[1:]
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTimed(t *testing.T) {
+	// Setup fixture
+	var out bytes.Buffer
+	clock := &fakeClock{}
+	timed := progress.NewTimedWithClock(progress.NewTerminalWithClock(&out, clock.now), clock.now)
+	expected := []progress.StageTiming{
+		{Stage: "Second", Duration: time.Second},
+		{Stage: "First", Duration: 3 * time.Second},
+	}
+
+	// Exercise SUT
+	timed.Start("First", 10)
+	clock.advance(time.Second)
+	timed.Start("Second", 0)
+	timed.Update("First", 5)
+	clock.advance(time.Second)
+	timed.Finish("Second")
+	clock.advance(time.Second)
+	timed.Finish("First")
+	timed.Finish("Unknown")
+
+	// Verify result
+	actual := timed.Timings()
+	if len(actual) != len(expected) {
+		t.Fatalf("Result differs. Actual: %v, Expected: %v", actual, expected)
+	}
+	for i := range actual {
+		if actual[i] != expected[i] {
+			t.Errorf("Result differs. Actual: %v, Expected: %v", actual, expected)
+		}
+	}
+	if !strings.Contains(out.String(), "First: DONE in 0:03") {
+		t.Errorf("Expected stages to be passed on, but got: %q", out.String())
+	}
+}
@@ -0,0 +1,161 @@
+package sequence_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/sequence"
+)
+
+func TestNewSmoother_WhenNameIsValid(t *testing.T) {
+	// Setup fixture
+	options := sequence.Options{Low: 0.4, High: 0.6, Closing: 2, Opening: 3}
+	var tests = []struct {
+		name     string
+		expected sequence.Smoother
+	}{
+		{"morphological", sequence.Morphological{Low: 0.4, High: 0.6, Closing: 2, Opening: 3}},
+		{"hmm", sequence.NewHMM(3)},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, err := sequence.NewSmoother(test.name, options)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewSmoother_WhenNameIsUnknown(t *testing.T) {
+	// Exercise SUT
+	_, err := sequence.NewSmoother("median", sequence.Options{})
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+func TestMorphological_Smooth(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		smoother    sequence.Morphological
+		confidences []float64
+		expected    []bool
+	}{
+		// Empty
+		{
+			sequence.Morphological{Low: 0.5, High: 0.5, Closing: 3, Opening: 3},
+			confidences(),
+			bools(),
+		},
+		// Thresholded only
+		{
+			sequence.Morphological{Low: 0.5, High: 0.5},
+			confidences(0, 1, 0, 1, 1, 0),
+			bools(0, 1, 0, 1, 1, 0),
+		},
+		// Hysteresis
+		{
+			sequence.Morphological{Low: 0.3, High: 0.7},
+			confidences(0.5, 0.8, 0.5, 0.2, 0.5, 0.5),
+			bools(1, 1, 1, 0, 0, 0),
+		},
+		// Gap closed, then short intertitle opened
+		{
+			sequence.Morphological{Low: 0.5, High: 0.5, Closing: 3, Opening: 3},
+			confidences(1, 1, 0, 1, 1, 0, 0, 0, 1, 1, 0),
+			bools(1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0),
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := test.smoother.Smooth(test.confidences)
+
+			// Verify result
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestHMM_Smooth(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		minRun      uint
+		confidences []float64
+		expected    []bool
+	}{
+		// Empty
+		{
+			3,
+			confidences(),
+			bools(),
+		},
+		// No smoothing -> Like a threshold
+		{
+			0,
+			confidences(0, 1, 0.4, 0.6, 1, 0),
+			bools(0, 1, 0, 1, 1, 0),
+		},
+		// Flickers shorter than the minimum run are smoothed over
+		{
+			3,
+			confidences(0, 0, 0, 1, 0, 0, 0, 1, 1, 0, 1, 1, 1, 0, 0, 0),
+			bools(0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 0, 0, 0),
+		},
+		// Runs as long as the minimum run are kept
+		{
+			3,
+			confidences(0, 0, 0, 1, 1, 1, 0, 0, 0, 1, 1, 1, 0, 0, 0),
+			bools(0, 0, 0, 1, 1, 1, 0, 0, 0, 1, 1, 1, 0, 0, 0),
+		},
+		// Less confident frames are easier to overrule
+		{
+			3,
+			confidences(1, 1, 1, 0.4, 0.3, 0.4, 0.3, 1, 1, 1),
+			bools(1, 1, 1, 1, 1, 1, 1, 1, 1, 1),
+		},
+		{
+			3,
+			confidences(1, 1, 1, 0, 0, 0, 0, 1, 1, 1),
+			bools(1, 1, 1, 0, 0, 0, 0, 1, 1, 1),
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := sequence.NewHMM(test.minRun).Smooth(test.confidences)
+
+			// Verify result
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func confidences(confidences ...float64) []float64 {
+	return append([]float64{}, confidences...)
+}
+
+func bools(items ...int) []bool {
+	bools := make([]bool, len(items))
+	for i, item := range items {
+		bools[i] = item > 0
+	}
+	return bools
+}
@@ -318,6 +318,45 @@ func TestAlignSubtitles_ShouldCopyStyles(t *testing.T) {
 	}
 }
 
+func TestAlignSubtitlesWithAssignments(t *testing.T) {
+	// Setup fixture
+	inputSubs := subs(
+		sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 6, 0), "text1"),
+		sub(timestamp(0, 0, 7, 0), timestamp(0, 0, 10, 0), "text2"),
+		sub(timestamp(0, 0, 11, 0), timestamp(0, 0, 14, 0), "text3"),
+		sub(timestamp(0, 1, 0, 0), timestamp(0, 1, 2, 0), "text4"),
+	)
+	first := interRange(0, 3, 1.0)
+	second := interRange(5, 15, 1.0)
+	inputInterRanges := interRanges(first, second)
+
+	// Setup expectations
+	expected := []subtitle.Assignment{
+		{Subtitle: inputSubs[0], Intertitle: first, Matched: true},
+		{Subtitle: inputSubs[1], Intertitle: second, Matched: true},
+		{Subtitle: inputSubs[2], Intertitle: second, Matched: true},
+		// No intertitle overlaps the last subtitle
+		{Subtitle: inputSubs[3]},
+	}
+
+	// Exercise SUT
+	_, actual := subtitle.AlignSubtitlesWithAssignments(inputSubs, inputInterRanges)
+
+	// Verify result
+	if len(actual) != len(expected) {
+		t.Fatalf("Result differs. Actual assignment count: %d, Expected: %d", len(actual), len(expected))
+	}
+	for i := range actual {
+		if actual[i].Subtitle.Text != expected[i].Subtitle.Text ||
+			!actual[i].Subtitle.StartTime.Equal(expected[i].Subtitle.StartTime) ||
+			actual[i].Matched != expected[i].Matched ||
+			actual[i].Intertitle.StartFrame != expected[i].Intertitle.StartFrame ||
+			actual[i].Intertitle.EndFrame != expected[i].Intertitle.EndFrame {
+			t.Errorf("Result differs at %d. Actual: %+v, Expected: %+v", i, actual[i], expected[i])
+		}
+	}
+}
+
 func subs(subs ...subtitle.Subtitle) []subtitle.Subtitle {
 	return subs
 }
@@ -0,0 +1,183 @@
+package read_test
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/file"
+	"github.com/liampulles/cabiria/pkg/subtitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/read"
+	subTest "github.com/liampulles/cabiria/pkg/subtitle/test"
+)
+
+func TestSubtitles_WhenValid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		path     string
+		expected []subtitle.Subtitle
+	}{
+		{
+			"many.vtt",
+			subs(
+				withSpans(
+					sub("First line\nSecond & line", timestamp("00:02:31,567"), timestamp("00:02:37,164")),
+					italic("First line"), plain("\nSecond & line"),
+				),
+				sub("Only line", timestamp("00:01:11,111"), timestamp("00:02:22,222")),
+			),
+		},
+		{
+			"many.ass",
+			subs(
+				sub("First line\nSecond, line", timestamp("00:02:31,560"), timestamp("00:02:37,160")),
+				sub("Only line", timestamp("00:01:11,110"), timestamp("00:02:22,220")),
+			),
+		},
+		{
+			"escaped.ass",
+			subs(
+				sub(`{Sic} a b c\N d\e`, timestamp("00:00:01,000"), timestamp("00:00:02,000")),
+			),
+		},
+		{
+			"many.ssa",
+			subs(
+				sub("First line\nSecond, line", timestamp("00:02:31,560"), timestamp("00:02:37,160")),
+			),
+		},
+		{
+			"many.sub",
+			subs(
+				sub("First line\nSecond line", timestamp("00:02:31,560"), timestamp("00:02:37,160")),
+				sub("Only line", timestamp("00:01:11,110"), timestamp("00:02:22,220")),
+			),
+		},
+		// Unknown extension -> Detected from contents
+		{
+			"vtt.txt",
+			subs(
+				withSpans(
+					sub("First line\nSecond & line", timestamp("00:02:31,567"), timestamp("00:02:37,164")),
+					italic("First line"), plain("\nSecond & line"),
+				),
+				sub("Only line", timestamp("00:01:11,111"), timestamp("00:02:22,222")),
+			),
+		},
+		{
+			"srt.txt",
+			subs(
+				sub("First line\nSecond line", timestamp("00:01:11,111"), timestamp("00:02:22,222")),
+				withSpans(
+					sub("First line\nSecond line", timestamp("00:02:22,222"), timestamp("00:03:33,333")),
+					plain("First "), italic("line"), plain("\nSecond line"),
+				),
+				withSpans(
+					sub("First line\nSecond line", timestamp("00:00:00,000"), timestamp("23:59:59,999")),
+					plain("First "), italic("line"), plain("\nSecond line"),
+				),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%s]", test.path), func(t *testing.T) {
+			// Exercise SUT
+			actual, _, err := read.Subtitles(path.Join("testdata", test.path), "")
+
+			// Verify result
+			if err != nil {
+				t.Errorf("Encountered exception in SUT: %v", err)
+			}
+			if err = subTest.CompareSubtitles(actual, test.expected); err != nil {
+				t.Errorf("Comparison failure: %v", err)
+			}
+		})
+	}
+}
+
+func TestSubtitles_WhenEncoded(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		path     string
+		encoding string
+		expected string
+	}{
+		// Detected
+		{
+			"cyrillic.srt",
+			"",
+			"Привет, мир!",
+		},
+		{
+			"latin.srt",
+			"",
+			"Città è bella",
+		},
+		// Given
+		{
+			"latin.srt",
+			file.ISO88591,
+			"Città è bella",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%s %s]", test.path, test.encoding), func(t *testing.T) {
+			// Exercise SUT
+			actual, _, err := read.Subtitles(path.Join("testdata", test.path), test.encoding)
+
+			// Verify result
+			if err != nil {
+				t.Fatalf("Encountered exception in SUT: %v", err)
+			}
+			if len(actual) != 1 || actual[0].Text != test.expected {
+				t.Errorf("Result differs. Actual: %+v, Expected text: %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestSubtitles_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		path     string
+		expected string
+	}{
+		{
+			"invalid.vtt",
+			"testdata/invalid.vtt:3: expected a cue timing, but found \"00:01.000 -> 00:02.000\"",
+		},
+		{
+			"invalid.ass",
+			"testdata/invalid.ass:3: expected 10 fields in the dialogue, but found 4",
+		},
+		{
+			"invalid.sub",
+			"testdata/invalid.sub:3: expected a timing like 00:00:01.00,00:00:02.00, but found \"Not a timing\"",
+		},
+		{
+			"unknown.txt",
+			"cannot tell what format the subtitles in testdata/unknown.txt are in. Use one of the extensions: .ass, .srt, .ssa, .sub, .vtt",
+		},
+		{
+			"missing.vtt",
+			"open testdata/missing.vtt: no such file or directory",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%s]", test.path), func(t *testing.T) {
+			// Exercise SUT
+			_, _, err := read.Subtitles(path.Join("testdata", test.path), "")
+
+			// Verify result
+			if err == nil {
+				t.Fatalf("Expected SUT to return an error")
+			}
+			if err.Error() != test.expected {
+				t.Errorf("Unexpected error.\nActual: %v\nExpected: %v", err, test.expected)
+			}
+		})
+	}
+}
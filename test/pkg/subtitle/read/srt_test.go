@@ -3,11 +3,13 @@ package read_test
 import (
 	"fmt"
 	"path"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/liampulles/cabiria/pkg/subtitle"
 	"github.com/liampulles/cabiria/pkg/subtitle/read"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
 	subTest "github.com/liampulles/cabiria/pkg/subtitle/test"
 	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
 )
@@ -44,15 +46,21 @@ func TestSRT_WhenSRTValid(t *testing.T) {
 					timestamp("00:01:11,111"),
 					timestamp("00:02:22,222"),
 				),
-				sub(
-					"First line\nSecond line",
-					timestamp("00:02:22,222"),
-					timestamp("00:03:33,333"),
+				withSpans(
+					sub(
+						"First line\nSecond line",
+						timestamp("00:02:22,222"),
+						timestamp("00:03:33,333"),
+					),
+					plain("First "), italic("line"), plain("\nSecond line"),
 				),
-				sub(
-					"First line\nSecond line",
-					timestamp("00:00:00,000"),
-					timestamp("23:59:59,999"),
+				withSpans(
+					sub(
+						"First line\nSecond line",
+						timestamp("00:00:00,000"),
+						timestamp("23:59:59,999"),
+					),
+					plain("First "), italic("line"), plain("\nSecond line"),
 				),
 			),
 		},
@@ -61,7 +69,7 @@ func TestSRT_WhenSRTValid(t *testing.T) {
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("[%s]", test.path), func(t *testing.T) {
 			// Exercise SUT
-			actual, err := read.SRT(path.Join("testdata", test.path))
+			actual, _, err := read.SRT(path.Join("testdata", test.path), "")
 
 			// Verify result
 			if err != nil {
@@ -74,6 +82,52 @@ func TestSRT_WhenSRTValid(t *testing.T) {
 	}
 }
 
+func TestSRT_WhenSRTMessy(t *testing.T) {
+	// Setup fixture
+	expected := subs(
+		sub("First\n1914", timestamp("00:00:01,000"), timestamp("00:00:02,000")),
+		sub("Second", timestamp("00:00:03,500"), timestamp("00:00:04,000")),
+		sub("Third\nStill third", timestamp("00:00:05,000"), timestamp("00:00:06,000")),
+		sub("Fourth", timestamp("25:00:00,000"), timestamp("25:00:01,000")),
+	)
+	expectedWarnings := []read.Warning{
+		{Line: 5, Message: "missing blank line before subtitle"},
+		{Line: 9, Message: "subtitle has no index"},
+		{Line: 11, Message: "blank line within subtitle text"},
+		{Line: 14, Message: "subtitle index 7 is out of order, expected 4"},
+	}
+
+	// Exercise SUT
+	actual, actualWarnings, err := read.SRT(path.Join("testdata", "messy.srt"), "")
+
+	// Verify result
+	if err != nil {
+		t.Errorf("Encountered exception in SUT: %v", err)
+	}
+	if err = subTest.CompareSubtitles(actual, expected); err != nil {
+		t.Errorf("Comparison failure: %v", err)
+	}
+	if !reflect.DeepEqual(actualWarnings, expectedWarnings) {
+		t.Errorf("Warnings differ.\nActual: %v\nExpected: %v", actualWarnings, expectedWarnings)
+	}
+}
+
+func TestSRT_WhenSRTInvalid(t *testing.T) {
+	// Setup fixture
+	expected := "testdata/invalid.srt:2: invalid timecode \"00:00:99,000\": minutes and seconds must be below 60"
+
+	// Exercise SUT
+	_, _, err := read.SRT(path.Join("testdata", "invalid.srt"), "")
+
+	// Verify result
+	if err == nil {
+		t.Fatalf("Expected SUT to return an error")
+	}
+	if err.Error() != expected {
+		t.Errorf("Unexpected error.\nActual: %v\nExpected: %v", err, expected)
+	}
+}
+
 func subs(subs ...subtitle.Subtitle) []subtitle.Subtitle {
 	return subs
 }
@@ -86,6 +140,19 @@ func sub(text string, start, end time.Time) subtitle.Subtitle {
 	}
 }
 
+func withSpans(sub subtitle.Subtitle, spans ...style.Span) subtitle.Subtitle {
+	sub.Spans = spans
+	return sub
+}
+
+func plain(text string) style.Span {
+	return style.Span{Text: text}
+}
+
+func italic(text string) style.Span {
+	return style.Span{Text: text, Italic: true}
+}
+
 func timestamp(s string) time.Time {
 	t, err := cabiriaTime.FromSRTTimecode(s)
 	if err != nil {
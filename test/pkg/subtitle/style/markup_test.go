@@ -0,0 +1,182 @@
+package style_test
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
+	subTest "github.com/liampulles/cabiria/pkg/subtitle/test"
+)
+
+func TestParseSRTText(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		fixture  string
+		expected []style.Span
+	}{
+		// No tags
+		{
+			"",
+			nil,
+		},
+		{
+			"text",
+			spans(plain("text")),
+		},
+		{
+			"1 < 2",
+			spans(plain("1 < 2")),
+		},
+		// Single tags
+		{
+			"<i>text</i>",
+			spans(span("text", true, false, false, nil)),
+		},
+		{
+			"<B>text</B>",
+			spans(span("text", false, true, false, nil)),
+		},
+		{
+			"te<u>xt</u>",
+			spans(plain("te"), span("xt", false, false, true, nil)),
+		},
+		{
+			"<font color=\"#FF8000\">text</font>",
+			spans(span("text", false, false, false, rgb(0xFF, 0x80, 0x00))),
+		},
+		{
+			"<font color=FF8000>text</font>",
+			spans(span("text", false, false, false, rgb(0xFF, 0x80, 0x00))),
+		},
+		{
+			"<font face='Arial' color='red'>text</font>",
+			spans(span("text", false, false, false, rgb(0xFF, 0x00, 0x00))),
+		},
+		// Unclosed, unopened and unknown tags
+		{
+			"<i>text",
+			spans(span("text", true, false, false, nil)),
+		},
+		{
+			"text</i> more",
+			spans(plain("text more")),
+		},
+		{
+			"<v Narrator>te<t>xt",
+			spans(plain("text")),
+		},
+		{
+			"<font color=\"nonsense\">text</font>",
+			spans(plain("text")),
+		},
+		// Nested tags
+		{
+			"a <i>b <b>c</b> d</i> e",
+			spans(
+				plain("a "),
+				span("b ", true, false, false, nil),
+				span("c", true, true, false, nil),
+				span(" d", true, false, false, nil),
+				plain(" e"),
+			),
+		},
+		{
+			"<font color=\"red\">a <font color=\"blue\">b</font> c</font>",
+			spans(
+				span("a ", false, false, false, rgb(0xFF, 0x00, 0x00)),
+				span("b", false, false, false, rgb(0x00, 0x00, 0xFF)),
+				span(" c", false, false, false, rgb(0xFF, 0x00, 0x00)),
+			),
+		},
+		// Same formatting is joined
+		{
+			"<i>a</i><i>\nb</i>",
+			spans(span("a\nb", true, false, false, nil)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%s]", test.fixture), func(t *testing.T) {
+			// Exercise SUT
+			actual := style.ParseSRTText(test.fixture)
+
+			// Verify result
+			if err := subTest.CompareSpans(actual, test.expected); err != nil {
+				t.Errorf("Comparison failure: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	// Setup fixture
+	fixture := spans(plain("a "), span("b", true, true, true, rgb(0xFF, 0x00, 0x00)), plain(" c"))
+
+	// Exercise SUT
+	actual := style.PlainText(fixture)
+
+	// Verify result
+	if actual != "a b c" {
+		t.Errorf("Text differs. Actual: %s, Expected: %s", actual, "a b c")
+	}
+}
+
+func TestFormatted(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		fixture  []style.Span
+		expected bool
+	}{
+		{
+			nil,
+			false,
+		},
+		{
+			spans(plain("a"), plain("b")),
+			false,
+		},
+		{
+			spans(plain("a"), span("b", true, false, false, nil)),
+			true,
+		},
+		{
+			spans(span("a", false, false, false, rgb(0x00, 0x00, 0x00))),
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := style.Formatted(test.fixture)
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func spans(spans ...style.Span) []style.Span {
+	return spans
+}
+
+func plain(text string) style.Span {
+	return style.Span{Text: text}
+}
+
+func span(text string, italic, bold, underline bool, col color.Color) style.Span {
+	return style.Span{
+		Text:      text,
+		Italic:    italic,
+		Bold:      bold,
+		Underline: underline,
+		Color:     col,
+	}
+}
+
+func rgb(r, g, b uint8) color.Color {
+	return color.RGBA{R: r, G: g, B: b, A: 0xFF}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/liampulles/cabiria/pkg/intertitle"
 
 	"github.com/liampulles/cabiria/pkg/subtitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/read"
 	"github.com/liampulles/cabiria/pkg/subtitle/style"
 
 	"github.com/liampulles/cabiria/pkg/subtitle/write"
@@ -110,6 +111,241 @@ Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
 Dialogue: 0,0:00:01.00,0:00:02.00,cabiria,,0000,0000,0000,,{\c&HFFFFFF&\3c&H000000&}Hello\NWorld
 Dialogue: 0,0:01:12.35,0:12:32.09,cabiria,,0000,0000,0000,,{\c&HD0E0FF&\3c&H000000&}How is it going?
 
+`,
+		},
+		// Formatted sub
+		{
+			subs(
+				formattedSub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), interSty(color.White, color.Black),
+					style.Span{Text: "Hello", Italic: true},
+					style.Span{Text: " big\n", Italic: true, Bold: true, Underline: true},
+					style.Span{Text: "red", Color: color.RGBA{R: 0xFF, A: 0xFF}},
+					style.Span{Text: " world"},
+				),
+			),
+			sty("Arial", 20),
+			vidInfo("City Lights", 1280, 576),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - City Lights
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 1280
+PlayResY: 576
+Video Aspect Ratio: 0
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Arial,20,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:02.00,cabiria,,0000,0000,0000,,{\c&HFFFFFF&\3c&H000000&\i1}Hello{\b1\u1} big\N{\i0\b0\u0\c&H0000FF&}red{\c&HFFFFFF&} world
+
+`,
+		},
+		// Escaped text
+		{
+			subs(
+				sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), "a {b} c\\N d\\", interSty(color.White, color.Black)),
+			),
+			sty("Arial", 20),
+			vidInfo("City Lights", 1280, 576),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - City Lights
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 1280
+PlayResY: 576
+Video Aspect Ratio: 0
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Arial,20,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:02.00,cabiria,,0000,0000,0000,,{\c&HFFFFFF&\3c&H000000&}a \{b\} c\` + "\u2060" + `N d\` + "\u2060" + `
+
+`,
+		},
+		// Square pixels
+		{
+			subs(),
+			sty("Arial", 20),
+			anamorphicVidInfo("City Lights", 1280, 576, 1.0),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - City Lights
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 1280
+PlayResY: 576
+Video Aspect Ratio: 0
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Arial,20,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+
+`,
+		},
+		// Anamorphic 4:3 NTSC
+		{
+			subs(),
+			sty("Arial", 20),
+			anamorphicVidInfo("Nosferatu", 720, 480, 8.0/9.0),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - Nosferatu
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 640
+PlayResY: 480
+Video Aspect Ratio: c1.333333
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Arial,20,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+
+`,
+		},
+		// Anamorphic 16:9 PAL
+		{
+			subs(),
+			sty("Arial", 20),
+			anamorphicVidInfo("Metropolis", 720, 576, 64.0/45.0),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - Metropolis
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 1024
+PlayResY: 576
+Video Aspect Ratio: c1.777778
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Arial,20,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+
+`,
+		},
+		// Anamorphic 4:3 NTSC, declaring only its display aspect ratio
+		{
+			subs(),
+			sty("Arial", 20),
+			displayOnlyVidInfo("Nosferatu", 720, 480, 4.0/3.0),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - Nosferatu
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 640
+PlayResY: 480
+Video Aspect Ratio: c1.333333
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Arial,20,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+
+`,
+		},
+		// Square pixels, declaring only its display aspect ratio
+		{
+			subs(),
+			sty("Arial", 20),
+			displayOnlyVidInfo("City Lights", 1280, 576, 20.0/9.0),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - City Lights
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 1280
+PlayResY: 576
+Video Aspect Ratio: 0
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Arial,20,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,1000,0,5,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+
+`,
+		},
+		// Custom style
+		{
+			subs(),
+			style.Style{
+				FontName:       "Tryst",
+				FontSize:       36,
+				Outline:        4,
+				Alignment:      2,
+				MarginLeft:     20,
+				MarginRight:    30,
+				MarginVertical: 40,
+			},
+			vidInfo("City Lights", 1280, 576),
+			`[Script Info]
+; Script generated by Cabiria v0.1.3
+; https://github.com/liampulles/cabiria
+Title: Cabiria Styled Subs - City Lights
+ScriptType: v4.00+
+WrapStyle: 0
+PlayResX: 1280
+PlayResY: 576
+Video Aspect Ratio: 0
+Video Zoom: 6
+Video Position: 0
+Collisions: Normal
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: cabiria,Tryst,36,&HFFFFFF,&HFF000000,&H00000000,&H000000,0,0,0,0,100,100,0,0,3,4,0,2,20,30,40,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+
 `,
 		},
 	}
@@ -131,6 +367,68 @@ Dialogue: 0,0:01:12.35,0:12:32.09,cabiria,,0000,0000,0000,,{\c&HD0E0FF&\3c&H0000
 	}
 }
 
+func TestASS_RoundTrip(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		sub      subtitle.Subtitle
+		expected string
+	}{
+		{
+			sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), "Hello, World", interSty(color.White, color.Black)),
+			"Hello, World",
+		},
+		{
+			sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), "Hello\nWorld", interSty(color.White, color.Black)),
+			"Hello\nWorld",
+		},
+		{
+			sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), "{Hello} World}", interSty(color.White, color.Black)),
+			"{Hello} World}",
+		},
+		{
+			sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), `{\i1}Hello\NWorld\h\`, interSty(color.White, color.Black)),
+			`{\i1}Hello\NWorld\h\`,
+		},
+		{
+			sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), `\\{}\{\}`, interSty(color.White, color.Black)),
+			`\\{}\{\}`,
+		},
+		{
+			formattedSub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), interSty(color.White, color.Black),
+				style.Span{Text: "{a}", Italic: true},
+				style.Span{Text: `\b`, Color: color.RGBA{R: 0xFF, A: 0xFF}},
+			),
+			`{a}\b`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%s]", test.expected), func(t *testing.T) {
+			// Exercise SUT
+			err := write.ASS(subs(test.sub), sty("Arial", 20), vidInfo("City Lights", 1280, 576), "/tmp/cabiria/assTest.ass")
+			if err != nil {
+				t.Errorf("SUT returned an error: %v", err)
+			}
+			actual, _, err := read.ASS("/tmp/cabiria/assTest.ass", "")
+
+			// Verify result
+			if err != nil {
+				t.Errorf("Could not read the ASS: %v", err)
+			}
+			if len(actual) != 1 {
+				t.Fatalf("Expected 1 subtitle, but found %d", len(actual))
+			}
+			if actual[0].Text != test.expected {
+				t.Errorf("Text differs. Actual: %q, Expected: %q", actual[0].Text, test.expected)
+			}
+			if !actual[0].StartTime.Equal(test.sub.StartTime) || !actual[0].EndTime.Equal(test.sub.EndTime) {
+				t.Errorf("Times differ. Actual: %v - %v, Expected: %v - %v",
+					actual[0].StartTime, actual[0].EndTime, test.sub.StartTime, test.sub.EndTime)
+			}
+		})
+	}
+}
+
 func readActual() string {
 	content, err := ioutil.ReadFile("/tmp/cabiria/assTest.ass")
 	if err != nil {
@@ -152,6 +450,15 @@ func sub(start, end time.Time, text string, style intertitle.Style) subtitle.Sub
 	}
 }
 
+func formattedSub(start, end time.Time, interStyle intertitle.Style, spans ...style.Span) subtitle.Subtitle {
+	result := sub(start, end, "", interStyle)
+	for _, elem := range spans {
+		result.Text += elem.Text
+	}
+	result.Spans = spans
+	return result
+}
+
 func interSty(foreground, background color.Color) intertitle.Style {
 	return intertitle.Style{
 		ForegroundColor: foreground,
@@ -160,10 +467,7 @@ func interSty(foreground, background color.Color) intertitle.Style {
 }
 
 func sty(fontName string, fontSize uint) style.Style {
-	return style.Style{
-		FontName: fontName,
-		FontSize: fontSize,
-	}
+	return style.Default(fontName, fontSize)
 }
 
 func vidInfo(videoName string, videoWidth, videoHeight int) testVideoInformation {
@@ -174,6 +478,24 @@ func vidInfo(videoName string, videoWidth, videoHeight int) testVideoInformation
 	}
 }
 
+func anamorphicVidInfo(videoName string, videoWidth, videoHeight int, sar float64) testVideoInformation {
+	return testVideoInformation{
+		videoName:              videoName,
+		videoWidth:             videoWidth,
+		videoHeight:            videoHeight,
+		videoSampleAspectRatio: sar,
+	}
+}
+
+func displayOnlyVidInfo(videoName string, videoWidth, videoHeight int, dar float64) testVideoInformation {
+	return testVideoInformation{
+		videoName:               videoName,
+		videoWidth:              videoWidth,
+		videoHeight:             videoHeight,
+		videoDisplayAspectRatio: dar,
+	}
+}
+
 func timestamp(hour, min, sec, milli int) time.Time {
 	return time.Date(0, time.January, 1, hour, min, sec, milli*1e+6, time.UTC)
 }
@@ -188,9 +510,11 @@ func greenishPink() color.Color {
 }
 
 type testVideoInformation struct {
-	videoName   string
-	videoWidth  int
-	videoHeight int
+	videoName               string
+	videoWidth              int
+	videoHeight             int
+	videoSampleAspectRatio  float64
+	videoDisplayAspectRatio float64
 }
 
 func (t testVideoInformation) VideoPath() string {
@@ -204,3 +528,11 @@ func (t testVideoInformation) VideoWidth() int {
 func (t testVideoInformation) VideoHeight() int {
 	return t.videoHeight
 }
+
+func (t testVideoInformation) VideoSampleAspectRatio() float64 {
+	return t.videoSampleAspectRatio
+}
+
+func (t testVideoInformation) VideoDisplayAspectRatio() float64 {
+	return t.videoDisplayAspectRatio
+}
@@ -0,0 +1,154 @@
+package write_test
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/subtitle"
+	"github.com/liampulles/cabiria/pkg/subtitle/read"
+	"github.com/liampulles/cabiria/pkg/subtitle/style"
+	subTest "github.com/liampulles/cabiria/pkg/subtitle/test"
+
+	"github.com/liampulles/cabiria/pkg/subtitle/write"
+)
+
+func TestSRT(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		subs     []subtitle.Subtitle
+		expected string
+	}{
+		// No subs
+		{
+			subs(),
+			``,
+		},
+		// One sub
+		{
+			subs(
+				sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), "Hello\nWorld", interSty(color.White, color.Black)),
+			),
+			`1
+00:00:01,000 --> 00:00:02,000
+Hello
+World
+
+`,
+		},
+		// Many subs
+		{
+			subs(
+				sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), "Hello\nWorld", interSty(color.White, color.Black)),
+				sub(timestamp(0, 1, 12, 354), timestamp(0, 12, 32, 90), "How is it going?", interSty(greenishPink(), color.Black)),
+			),
+			`1
+00:00:01,000 --> 00:00:02,000
+Hello
+World
+
+2
+00:01:12,354 --> 00:12:32,090
+How is it going?
+
+`,
+		},
+		// Formatted sub
+		{
+			subs(
+				formattedSub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), interSty(color.White, color.Black),
+					style.Span{Text: "Hello", Italic: true},
+					style.Span{Text: " big\n", Italic: true, Bold: true, Underline: true},
+					style.Span{Text: "red", Color: color.RGBA{R: 0xFF, A: 0xFF}},
+					style.Span{Text: " world"},
+				),
+			),
+			`1
+00:00:01,000 --> 00:00:02,000
+<i>Hello<b><u> big
+</u></b></i><font color="#ff0000">red</font> world
+
+`,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			err := write.SRT(test.subs, "/tmp/cabiria/srtTest.srt")
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT returned an error: %v", err)
+			}
+			actual := readActualSRT()
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual:\n%sExpected:\n%s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestSRT_Roundtrip(t *testing.T) {
+	// Setup fixture
+	fixture := subs(
+		sub(timestamp(0, 0, 1, 0), timestamp(0, 0, 2, 0), "Hello\nWorld", interSty(nil, nil)),
+		sub(timestamp(0, 1, 12, 354), timestamp(0, 12, 32, 90), "How is it going?", interSty(nil, nil)),
+	)
+
+	// Exercise SUT
+	err := write.SRT(fixture, "/tmp/cabiria/srtRoundtripTest.srt")
+	if err != nil {
+		t.Errorf("SUT returned an error: %v", err)
+	}
+	actual, _, err := read.SRT("/tmp/cabiria/srtRoundtripTest.srt", "")
+
+	// Verify result
+	if err != nil {
+		t.Errorf("read.SRT returned an error: %v", err)
+	}
+	if err = subTest.CompareSubtitles(actual, fixture); err != nil {
+		t.Errorf("Comparison failure: %v", err)
+	}
+}
+
+func TestSRT_ReadWriteRoundtrip(t *testing.T) {
+	// Setup fixture
+	fixture := "testdata/formatted.srt"
+	expected, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("Could not read fixture: %v", err)
+	}
+	subs, _, err := read.SRT(fixture, "")
+	if err != nil {
+		t.Fatalf("read.SRT returned an error: %v", err)
+	}
+
+	// Exercise SUT
+	err = write.SRT(subs, "/tmp/cabiria/srtTest.srt")
+
+	// Verify result
+	if err != nil {
+		t.Errorf("SUT returned an error: %v", err)
+	}
+	actual := readActualSRT()
+	if actual != string(expected) {
+		t.Errorf("Result differs. Actual:\n%sExpected:\n%s", actual, expected)
+	}
+	reread, _, err := read.SRT("/tmp/cabiria/srtTest.srt", "")
+	if err != nil {
+		t.Errorf("read.SRT returned an error: %v", err)
+	}
+	if err = subTest.CompareSubtitles(reread, subs); err != nil {
+		t.Errorf("Comparison failure: %v", err)
+	}
+}
+
+func readActualSRT() string {
+	content, err := ioutil.ReadFile("/tmp/cabiria/srtTest.srt")
+	if err != nil {
+		panic(err)
+	}
+	return string(content)
+}
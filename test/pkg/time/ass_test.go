@@ -26,6 +26,11 @@ func TestToASSTimecode(t *testing.T) {
 			timestamp(12, 34, 56, 90),
 			"12:34:56.09",
 		},
+		// 24 hours or more
+		{
+			timestamp(25, 0, 1, 0),
+			"25:00:01.00",
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,63 @@
+package time_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	cabiriaTime "github.com/liampulles/cabiria/pkg/time"
+)
+
+func TestToTimecode(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		t        time.Time
+		digits   int
+		expected string
+	}{
+		{
+			timestamp(0, 0, 0, 0),
+			3,
+			"00:00:00.000",
+		},
+		{
+			timestamp(1, 23, 45, 678),
+			3,
+			"01:23:45.678",
+		},
+		{
+			timestamp(1, 23, 45, 678),
+			0,
+			"01:23:45",
+		},
+		{
+			timestamp(1, 23, 45, 678).Add(9 * time.Nanosecond),
+			9,
+			"01:23:45.678000009",
+		},
+		// Truncated
+		{
+			timestamp(12, 34, 56, 999),
+			2,
+			"12:34:56.99",
+		},
+		// 24 hours or more
+		{
+			timestamp(25, 0, 1, 50),
+			3,
+			"25:00:01.050",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s -> %s", test.t.String(), test.expected), func(t *testing.T) {
+			// Exercise SUT
+			actual := cabiriaTime.ToTimecode(test.t, test.digits)
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %s, Expected %s", actual, test.expected)
+			}
+		})
+	}
+}
@@ -42,6 +42,34 @@ func TestFromSRTTimecode_WhenInputIsValid(t *testing.T) {
 			"12:34:56,789",
 			timestamp(12, 34, 56, 789),
 		},
+		// Period instead of comma
+		{
+			"00:01:02.345",
+			timestamp(0, 1, 2, 345),
+		},
+		// Fewer or more fractional digits, or none
+		{
+			"00:01:02.5",
+			timestamp(0, 1, 2, 500),
+		},
+		{
+			"0:1:2,045",
+			timestamp(0, 1, 2, 45),
+		},
+		{
+			"00:01:02",
+			timestamp(0, 1, 2, 0),
+		},
+		// 24 hours or more
+		{
+			"25:00:00,000",
+			timestamp(25, 0, 0, 0),
+		},
+		// Surrounding space
+		{
+			" 00:00:01,000 ",
+			timestamp(0, 0, 1, 0),
+		},
 	}
 
 	for i, test := range tests {
@@ -59,3 +87,98 @@ func TestFromSRTTimecode_WhenInputIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestFromSRTTimecode_WhenInputIsInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []string{
+		"",
+		"00:00",
+		"00:00:00,",
+		"00:60:00,000",
+		"00:00:60,000",
+		"-1:00:00,000",
+		"00:00:00;000",
+		"aa:bb:cc,ddd",
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("[%s]", test), func(t *testing.T) {
+			// Exercise SUT
+			_, err := cabiriaTime.FromSRTTimecode(test)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to return an error")
+			}
+		})
+	}
+}
+
+func TestToSRTTimecode(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		t        time.Time
+		expected string
+	}{
+		{
+			timestamp(0, 0, 0, 0),
+			"00:00:00,000",
+		},
+		{
+			timestamp(1, 23, 45, 678),
+			"01:23:45,678",
+		},
+		{
+			timestamp(12, 34, 56, 90),
+			"12:34:56,090",
+		},
+		{
+			timestamp(23, 59, 59, 999),
+			"23:59:59,999",
+		},
+		// 24 hours or more
+		{
+			timestamp(25, 0, 1, 0),
+			"25:00:01,000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s -> %s", test.t.String(), test.expected), func(t *testing.T) {
+			// Exercise SUT
+			actual := cabiriaTime.ToSRTTimecode(test.t)
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %s, Expected %s", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestSRTTimecode_RoundTrip(t *testing.T) {
+	// Setup fixture
+	var tests = []string{
+		"00:00:00,000",
+		"12:34:56,789",
+		"23:59:59,999",
+		"25:00:01,000",
+		"100:00:00,001",
+	}
+
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			// Exercise SUT
+			parsed, err := cabiriaTime.FromSRTTimecode(test)
+			if err != nil {
+				t.Fatalf("SUT threw an error: %v", err)
+			}
+			actual := cabiriaTime.ToSRTTimecode(parsed)
+
+			// Verify result
+			if actual != test {
+				t.Errorf("Result differs. Actual: %s, Expected %s", actual, test)
+			}
+		})
+	}
+}
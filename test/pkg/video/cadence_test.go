@@ -0,0 +1,199 @@
+package video_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/video"
+)
+
+func TestCadence_FilmFPS(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		cadence   video.Cadence
+		streamFPS float64
+		expected  float64
+	}{
+		// Invalid cadences
+		{
+			video.Cadence{},
+			25.0,
+			25.0,
+		},
+		{
+			video.Cadence{Cycle: 2, Repeats: 2},
+			25.0,
+			25.0,
+		},
+		// No repeats
+		{
+			video.Cadence{Cycle: 1},
+			25.0,
+			25.0,
+		},
+		// 3:2 pulldown
+		{
+			video.Cadence{Cycle: 5, Repeats: 1},
+			30.0,
+			24.0,
+		},
+		// 16 FPS film in 24 FPS video
+		{
+			video.Cadence{Cycle: 3, Repeats: 1},
+			24.0,
+			16.0,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := test.cadence.FilmFPS(test.streamFPS)
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestDetectCadence(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		duplicates []bool
+		expected   video.Cadence
+	}{
+		// No frames
+		{
+			nil,
+			video.Cadence{Cycle: 1},
+		},
+		// No duplicates
+		{
+			bools(0, 0, 0, 0, 0, 0),
+			video.Cadence{Cycle: 1},
+		},
+		// A static shot is not a cadence
+		{
+			bools(0, 0, 1, 1, 1, 1, 1, 1, 0, 0),
+			video.Cadence{Cycle: 1},
+		},
+		// 3:2 pulldown
+		{
+			repeat(bools(0, 0, 0, 0, 1), 20),
+			video.Cadence{Cycle: 5, Repeats: 1},
+		},
+		// 16 FPS film in 24 FPS video, with a static shot which shifts the
+		//  phase
+		{
+			concat(repeat(bools(0, 0, 1), 20), bools(1, 1, 1, 1, 0), repeat(bools(0, 1, 0), 20)),
+			video.Cadence{Cycle: 3, Repeats: 1},
+		},
+		// 16 FPS film in 30 FPS video
+		{
+			repeat(bools(0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0, 1, 0), 20),
+			video.Cadence{Cycle: 15, Repeats: 7},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := video.DetectCadence(test.duplicates)
+
+			// Verify result
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestCadenceDetector(t *testing.T) {
+	// Setup fixture
+	black := uniformImage(color.Black)
+	white := uniformImage(color.White)
+	frames := []image.Image{black, white, white, black, white, white, black, white, white}
+	var detector video.CadenceDetector
+
+	// Exercise SUT
+	for _, frame := range frames {
+		err := detector.Add(frame)
+		if err != nil {
+			t.Fatalf("SUT returned an error: %v", err)
+		}
+	}
+	actual := detector.Cadence()
+
+	// Verify result
+	expected := video.Cadence{Cycle: 3, Repeats: 1}
+	if actual != expected {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, expected)
+	}
+}
+
+func TestCadenceDetector_AddDiff(t *testing.T) {
+	// Setup fixture
+	diffs := []float64{0.0, 0.5, 0.0, 0.5, 0.5, 0.0, 0.5, 0.5, 0.0}
+	var detector video.CadenceDetector
+
+	// Exercise SUT
+	for _, diff := range diffs {
+		detector.AddDiff(diff)
+	}
+	actual := detector.Cadence()
+
+	// Verify result
+	expected := video.Cadence{Cycle: 3, Repeats: 1}
+	if actual != expected {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, expected)
+	}
+}
+
+func TestCadenceDetector_WhenBoundsDiffer(t *testing.T) {
+	// Setup fixture
+	var detector video.CadenceDetector
+	detector.Add(image.NewRGBA(image.Rect(0, 0, 2, 2)))
+
+	// Exercise SUT
+	err := detector.Add(image.NewRGBA(image.Rect(0, 0, 3, 3)))
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+func uniformImage(col color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 6))
+	draw.Draw(img, img.Bounds(), image.NewUniform(col), image.Point{}, draw.Src)
+	return img
+}
+
+func repeat(pattern []bool, times int) []bool {
+	result := make([]bool, 0)
+	for i := 0; i < times; i++ {
+		result = append(result, pattern...)
+	}
+	return result
+}
+
+func concat(arrays ...[]bool) []bool {
+	result := make([]bool, 0)
+	for _, array := range arrays {
+		result = append(result, array...)
+	}
+	return result
+}
+
+func bools(values ...int) []bool {
+	result := make([]bool, 0)
+	for _, value := range values {
+		result = append(result, value != 0)
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package video_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/liampulles/cabiria/pkg/video"
+)
+
+func TestNewProber(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		backend  string
+		expected video.Prober
+	}{
+		{
+			"ffprobe",
+			video.FFProbeProber{},
+		},
+		{
+			"mediainfo",
+			video.MediaInfoProber{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.backend, func(t *testing.T) {
+			// Exercise SUT
+			actual, err := video.NewProber(test.backend)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT returned an error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("Results differ - Expected: %v, Actual: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewProber_WhenBackendUnknown(t *testing.T) {
+	// Exercise SUT
+	_, err := video.NewProber("vlc")
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+func TestFFProbeProber_ForExistingVideo(t *testing.T) {
+	// Setup expectations
+	expected := video.Information{
+		Width:              656,
+		Height:             526,
+		FPS:                25.0,
+		Duration:           13200 * time.Millisecond,
+		FrameCount:         330,
+		SampleAspectRatio:  1.0,
+		DisplayAspectRatio: 328.0 / 263.0,
+	}
+
+	// Exercise SUT
+	actual, err := video.FFProbeProber{}.Probe(context.Background(), "testdata/By-The-Law.mkv")
+
+	// Verify result
+	if err != nil {
+		t.Errorf("SUT returned an error: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("Results differ - Expected: %v, Actual: %v", expected, actual)
+	}
+}
+
+func TestFFProbeProber_ForNonExistingVideo(t *testing.T) {
+	// Exercise SUT
+	_, err := video.FFProbeProber{}.Probe(context.Background(), "this/path/does/not.exist")
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+func TestFFProbeProber_WhenCancelled(t *testing.T) {
+	// Setup fixture
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Exercise SUT
+	_, err := video.FFProbeProber{}.Probe(ctx, "testdata/By-The-Law.mkv")
+
+	// Verify result
+	if err != context.Canceled {
+		t.Errorf("Result differs. Actual error: %v, Expected: %v", err, context.Canceled)
+	}
+}
+
+func TestParseFFProbeJSON_WhenValid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		path     string
+		expected video.Information
+	}{
+		{
+			"By-The-Law.ffprobe.json",
+			video.Information{
+				Width:              656,
+				Height:             526,
+				FPS:                25.0,
+				Duration:           13200 * time.Millisecond,
+				FrameCount:         330,
+				SampleAspectRatio:  1.0,
+				DisplayAspectRatio: 328.0 / 263.0,
+			},
+		},
+		{
+			"vfr.ffprobe.json",
+			video.Information{
+				Width:              720,
+				Height:             480,
+				FPS:                18.0,
+				Duration:           60 * time.Second,
+				FrameCount:         1080,
+				VariableFrameRate:  true,
+				StreamIndex:        1,
+				SampleAspectRatio:  8.0 / 9.0,
+				DisplayAspectRatio: 4.0 / 3.0,
+			},
+		},
+		// Cover art is skipped
+		{
+			"cover.ffprobe.json",
+			video.Information{
+				Width:       720,
+				Height:      480,
+				FPS:         24.0,
+				Duration:    10 * time.Second,
+				FrameCount:  240,
+				StreamIndex: 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			// Setup fixture
+			data, err := ioutil.ReadFile(path.Join("testdata", test.path))
+			if err != nil {
+				t.Fatalf("Could not read fixture: %v", err)
+			}
+
+			// Exercise SUT
+			actual, err := video.ParseFFProbeJSON(data)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT returned an error: %v", err)
+			}
+			if actual != test.expected {
+				t.Errorf("Results differ - Expected: %v, Actual: %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseFFProbeJSON_WhenInvalid(t *testing.T) {
+	// Setup fixture
+	noVideo, err := ioutil.ReadFile("testdata/noVideo.ffprobe.json")
+	if err != nil {
+		t.Fatalf("Could not read fixture: %v", err)
+	}
+	var tests = [][]byte{
+		[]byte(""),
+		[]byte("not json"),
+		[]byte(`{"streams": []}`),
+		[]byte(`{"streams": [{"codec_type": "video", "r_frame_rate": "0/0", "avg_frame_rate": "0/0"}]}`),
+		noVideo,
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			_, err := video.ParseFFProbeJSON(test)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to return an error")
+			}
+		})
+	}
+}
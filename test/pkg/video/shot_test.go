@@ -0,0 +1,126 @@
+package video_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/liampulles/cabiria/pkg/video"
+)
+
+func TestDetectShotBoundaries(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		diffs     []float64
+		lightness []float64
+		expected  video.ShotBoundaries
+	}{
+		// Nothing
+		{
+			nil,
+			nil,
+			video.ShotBoundaries{},
+		},
+		// Cut
+		{
+			[]float64{0.0, 0.01, 0.02, 0.5, 0.01, 0.02},
+			[]float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5},
+			video.ShotBoundaries{Cuts: []int{3}},
+		},
+		// Fast motion -> Not a cut
+		{
+			[]float64{0.0, 0.2, 0.25, 0.3, 0.2, 0.25},
+			[]float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5},
+			video.ShotBoundaries{},
+		},
+		// Fade in, then fade out
+		{
+			[]float64{0.0, 0.0, 0.1, 0.1, 0.1, 0.1, 0.0, 0.1, 0.1, 0.1, 0.1, 0.0},
+			[]float64{0.0, 0.0, 0.2, 0.4, 0.6, 0.8, 0.8, 0.6, 0.4, 0.2, 0.0, 0.0},
+			video.ShotBoundaries{
+				FadeIns:  []video.Fade{{Start: 2, End: 4}},
+				FadeOuts: []video.Fade{{Start: 7, End: 9}},
+			},
+		},
+		// Too short to be a fade
+		{
+			[]float64{0.0, 0.0, 0.1, 0.1, 0.0},
+			[]float64{0.0, 0.0, 0.4, 0.8, 0.8},
+			video.ShotBoundaries{},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual := video.DetectShotBoundaries(test.diffs, test.lightness)
+
+			// Verify result
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestShotBoundaries_StartsAndEnds(t *testing.T) {
+	// Setup fixture
+	fixture := video.ShotBoundaries{
+		Cuts:     []int{0, 10, 30},
+		FadeIns:  []video.Fade{{Start: 10, End: 12}, {Start: 20, End: 22}},
+		FadeOuts: []video.Fade{{Start: 25, End: 29}},
+	}
+
+	// Exercise SUT
+	actualStarts := fixture.Starts()
+	actualEnds := fixture.Ends()
+
+	// Verify result
+	expectedStarts := []int{0, 10, 20, 30}
+	expectedEnds := []int{9, 29}
+	if !reflect.DeepEqual(actualStarts, expectedStarts) {
+		t.Errorf("Starts differ. Actual: %v, Expected: %v", actualStarts, expectedStarts)
+	}
+	if !reflect.DeepEqual(actualEnds, expectedEnds) {
+		t.Errorf("Ends differ. Actual: %v, Expected: %v", actualEnds, expectedEnds)
+	}
+}
+
+func TestShotDetector(t *testing.T) {
+	// Setup fixture
+	gray := uniformImage(color.Gray{Y: 128})
+	white := uniformImage(color.White)
+	frames := []image.Image{gray, gray, gray, gray, white, white, white, white}
+	var detector video.ShotDetector
+
+	// Exercise SUT
+	for _, frame := range frames {
+		err := detector.Add(frame)
+		if err != nil {
+			t.Fatalf("SUT returned an error: %v", err)
+		}
+	}
+	actual := detector.Boundaries()
+
+	// Verify result
+	expected := video.ShotBoundaries{Cuts: []int{4}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, expected)
+	}
+}
+
+func TestShotDetector_WhenBoundsDiffer(t *testing.T) {
+	// Setup fixture
+	var detector video.ShotDetector
+	detector.Add(image.NewRGBA(image.Rect(0, 0, 2, 2)))
+
+	// Exercise SUT
+	err := detector.Add(image.NewRGBA(image.Rect(0, 0, 3, 3)))
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
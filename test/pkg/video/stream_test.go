@@ -0,0 +1,399 @@
+package video_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	imageTest "github.com/liampulles/cabiria/pkg/image/test"
+	"github.com/liampulles/cabiria/pkg/video"
+)
+
+func TestStreamFrames_ForExistingVideo(t *testing.T) {
+	// Setup fixture
+	count := 0
+	handler := func(index int, frame image.Image) error {
+		if index != count {
+			return fmt.Errorf("expected frame %d, but received frame %d", count, index)
+		}
+		if frame.Bounds().Dx() != 64 || frame.Bounds().Dy() != 48 {
+			return fmt.Errorf("unexpected frame bounds: %v", frame.Bounds())
+		}
+		count++
+		return nil
+	}
+
+	// Exercise SUT
+	timestamps, err := video.StreamFrames("testdata/By-The-Law.mkv", 64, 48, handler)
+
+	// Verify result
+	if err != nil {
+		t.Errorf("SUT threw an error: %v", err)
+	}
+	if count != 330 {
+		t.Errorf("Result differs. Actual frame count: %d, Expected: %d", count, 330)
+	}
+	if len(timestamps) != count {
+		t.Errorf("Result differs. Actual timestamp count: %d, Expected: %d", len(timestamps), count)
+	}
+	for i := 1; i < len(timestamps); i++ {
+		if timestamps[i] <= timestamps[i-1] {
+			t.Errorf("Expected timestamps to increase, but %v follows %v at frame %d", timestamps[i], timestamps[i-1], i)
+		}
+	}
+}
+
+func TestStreamFrames_ForNonExistingVideo(t *testing.T) {
+	// Exercise SUT
+	_, err := video.StreamFrames("this/path/does/not.exist", 64, 48, func(int, image.Image) error {
+		return nil
+	})
+
+	// Verify result
+	if err == nil {
+		t.Errorf("Expected SUT to return an error")
+	}
+}
+
+func TestStreamFramesContext_WhenCancelled(t *testing.T) {
+	// Setup fixture
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Exercise SUT
+	_, err := video.StreamFramesContext(ctx, "testdata/By-The-Law.mkv", 0, 64, 48, func(int, image.Image) error {
+		return nil
+	}, nil)
+
+	// Verify result
+	if err != context.Canceled {
+		t.Errorf("Result differs. Actual error: %v, Expected: %v", err, context.Canceled)
+	}
+}
+
+func TestParseShowInfoTimestamp(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		line       string
+		expected   time.Duration
+		expectedOk bool
+	}{
+		// Not showinfo frame lines
+		{
+			"",
+			0,
+			false,
+		},
+		{
+			"Input #0, matroska,webm, from 'film.mkv':",
+			0,
+			false,
+		},
+		{
+			"[Parsed_showinfo_1 @ 0x55d0c6b4c940] config in time_base: 1/1000, frame_rate: 25/1",
+			0,
+			false,
+		},
+		{
+			"[Parsed_showinfo_1 @ 0x55d0c6b4c940] n:   0 pts:      0 pts_time:nope",
+			0,
+			false,
+		},
+		// Frame lines
+		{
+			"[Parsed_showinfo_1 @ 0x55d0c6b4c940] n:   0 pts:      0 pts_time:0       pos:     4179 fmt:yuv420p",
+			0,
+			true,
+		},
+		{
+			"[Parsed_showinfo_1 @ 0x55d0c6b4c940] n:  12 pts:  48048 pts_time:0.5005  duration:   1001 duration_time:0.0333667 fmt:yuv420p",
+			500500 * time.Microsecond,
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			actual, ok := video.ParseShowInfoTimestamp(test.line)
+
+			// Verify result
+			if ok != test.expectedOk {
+				t.Errorf("Result differs. Actual ok: %v, Expected: %v", ok, test.expectedOk)
+			}
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %v, Expected: %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseProgressLine(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		line          string
+		expectedKey   string
+		expectedValue string
+		expectedOk    bool
+	}{
+		// Not progress lines
+		{
+			"",
+			"",
+			"",
+			false,
+		},
+		{
+			"Input #0, matroska,webm, from 'film.mkv':",
+			"",
+			"",
+			false,
+		},
+		{
+			"[Parsed_showinfo_1 @ 0x55d0c6b4c940] n:   0 pts:      0 pts_time:0",
+			"",
+			"",
+			false,
+		},
+		// Progress lines
+		{
+			"frame=1234",
+			"frame",
+			"1234",
+			true,
+		},
+		{
+			"out_time=00:00:49.360000",
+			"out_time",
+			"00:00:49.360000",
+			true,
+		},
+		{
+			"progress=end",
+			"progress",
+			"end",
+			true,
+		},
+		// Trailing carriage returns are ignored
+		{
+			"stream_0_0_q=-0.0\r",
+			"stream_0_0_q",
+			"-0.0",
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			key, value, ok := video.ParseProgressLine(test.line)
+
+			// Verify result
+			if ok != test.expectedOk {
+				t.Errorf("Result differs. Actual ok: %v, Expected: %v", ok, test.expectedOk)
+			}
+			if key != test.expectedKey || value != test.expectedValue {
+				t.Errorf("Result differs. Actual: %s=%s, Expected: %s=%s", key, value, test.expectedKey, test.expectedValue)
+			}
+		})
+	}
+}
+
+func TestProgress_Apply(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		lines            []string
+		expected         video.Progress
+		expectedComplete bool
+	}{
+		// Incomplete block
+		{
+			[]string{"frame=10", "fps=0.0"},
+			video.Progress{Frame: 10},
+			false,
+		},
+		// Complete block
+		{
+			[]string{"frame=10", "out_time_us=400000", "progress=continue"},
+			video.Progress{Frame: 10, OutTime: 400 * time.Millisecond},
+			true,
+		},
+		// Final block
+		{
+			[]string{"frame=260", "out_time_ms=10400000", "progress=end"},
+			video.Progress{Frame: 260, OutTime: 10400 * time.Millisecond, Done: true},
+			true,
+		},
+		// Unparseable values are ignored
+		{
+			[]string{"frame=10", "frame=N/A", "out_time_us=N/A", "progress=continue"},
+			video.Progress{Frame: 10},
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			var actual video.Progress
+			complete := false
+
+			// Exercise SUT
+			for _, line := range test.lines {
+				key, value, ok := video.ParseProgressLine(line)
+				if !ok {
+					t.Fatalf("Could not parse line: %s", line)
+				}
+				complete = actual.Apply(key, value)
+			}
+
+			// Verify result
+			if complete != test.expectedComplete {
+				t.Errorf("Result differs. Actual complete: %v, Expected: %v", complete, test.expectedComplete)
+			}
+			if actual != test.expected {
+				t.Errorf("Result differs. Actual: %+v, Expected: %+v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestReadRGBFrames_WhenInputIsValid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		raw      []byte
+		expected []image.Image
+	}{
+		// No frames
+		{
+			[]byte{},
+			images(),
+		},
+		// One frame
+		{
+			[]byte{
+				255, 0, 0, 0, 255, 0,
+				0, 0, 255, 10, 20, 30,
+			},
+			images(
+				img(
+					rgb(255, 0, 0), rgb(0, 255, 0),
+					rgb(0, 0, 255), rgb(10, 20, 30),
+				),
+			),
+		},
+		// Many frames
+		{
+			[]byte{
+				255, 0, 0, 0, 255, 0,
+				0, 0, 255, 10, 20, 30,
+				1, 2, 3, 4, 5, 6,
+				7, 8, 9, 10, 11, 12,
+			},
+			images(
+				img(
+					rgb(255, 0, 0), rgb(0, 255, 0),
+					rgb(0, 0, 255), rgb(10, 20, 30),
+				),
+				img(
+					rgb(1, 2, 3), rgb(4, 5, 6),
+					rgb(7, 8, 9), rgb(10, 11, 12),
+				),
+			),
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Setup fixture
+			var actual []image.Image
+			handler := func(index int, frame image.Image) error {
+				if index != len(actual) {
+					return fmt.Errorf("expected frame %d, but received frame %d", len(actual), index)
+				}
+				actual = append(actual, frame)
+				return nil
+			}
+
+			// Exercise SUT
+			err := video.ReadRGBFrames(bytes.NewReader(test.raw), 2, 2, handler)
+
+			// Verify result
+			if err != nil {
+				t.Errorf("SUT threw an error: %v", err)
+			}
+			if len(actual) != len(test.expected) {
+				t.Fatalf("Result differs. Actual frame count: %d, Expected: %d", len(actual), len(test.expected))
+			}
+			for j := range actual {
+				if err := imageTest.CompareImage(actual[j], test.expected[j]); err != nil {
+					t.Errorf("Frame %d differs: %v", j, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadRGBFrames_WhenInputIsInvalid(t *testing.T) {
+	// Setup fixture
+	var tests = []struct {
+		raw     []byte
+		width   int
+		height  int
+		handler video.FrameHandler
+	}{
+		// Incomplete frame
+		{
+			[]byte{255, 0, 0, 0, 255},
+			2,
+			2,
+			func(int, image.Image) error { return nil },
+		},
+		// Invalid dimensions
+		{
+			[]byte{},
+			0,
+			2,
+			func(int, image.Image) error { return nil },
+		},
+		// Handler fails
+		{
+			[]byte{255, 0, 0},
+			1,
+			1,
+			func(int, image.Image) error { return fmt.Errorf("handler failure") },
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			// Exercise SUT
+			err := video.ReadRGBFrames(bytes.NewReader(test.raw), test.width, test.height, test.handler)
+
+			// Verify result
+			if err == nil {
+				t.Errorf("Expected SUT to return an error")
+			}
+		})
+	}
+}
+
+func images(images ...image.Image) []image.Image {
+	return images
+}
+
+func img(cols ...color.Color) image.Image {
+	result := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for i, col := range cols {
+		result.Set(i%2, i/2, col)
+	}
+	return result
+}
+
+func rgb(r, g, b uint8) color.Color {
+	return color.RGBA{R: r, G: g, B: b, A: 255}
+}